@@ -0,0 +1,88 @@
+package board
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// StartFEN — начальная позиция в нотации FEN
+const StartFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+var fenPieces = map[rune]Piece{
+	'p': Pawn,
+	'n': Knight,
+	'b': Bishop,
+	'r': Rook,
+	'q': Queen,
+	'k': King,
+}
+
+// ParseFEN разбирает позицию в нотации FEN и возвращает доску и цвет стороны, которая ходит.
+// Права на рокировку, взятие на проходе и счётчики ходов игнорируются; счётчик полуходов
+// для правила пятидесяти ходов возвращает HalfmoveClock.
+func ParseFEN(fen string) (Board, Color, error) {
+	var b Board
+	fields := strings.Fields(fen)
+	if len(fields) == 0 {
+		return b, White, errors.New("пустая строка FEN")
+	}
+
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		return b, White, errors.New("в FEN должно быть 8 горизонталей")
+	}
+
+	for i, rank := range ranks {
+		x := 7 - i
+		y := 0
+		for _, r := range rank {
+			if r >= '1' && r <= '8' {
+				y += int(r - '0')
+				continue
+			}
+			piece, ok := fenPieces[r|0x20]
+			if !ok {
+				return b, White, errors.New("неизвестная фигура в FEN: " + string(r))
+			}
+			color := White
+			if r >= 'a' && r <= 'z' {
+				color = Black
+			}
+			if err := b.SetPiece(x, y, piece, color); err != nil {
+				return b, White, err
+			}
+			y++
+		}
+		if y != 8 {
+			return b, White, errors.New("некорректная длина горизонтали в FEN")
+		}
+	}
+
+	color := White
+	if len(fields) > 1 {
+		switch fields[1] {
+		case "w":
+		case "b":
+			color = Black
+		default:
+			return b, White, errors.New("некорректный цвет хода в FEN")
+		}
+	}
+
+	return b, color, nil
+}
+
+// HalfmoveClock возвращает из FEN число полуходов без взятий и ходов пешек
+// для правила пятидесяти ходов; 0, если поле отсутствует или некорректно
+func HalfmoveClock(fen string) int {
+	fields := strings.Fields(fen)
+	if len(fields) < 5 {
+		return 0
+	}
+	n, err := strconv.Atoi(fields[4])
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
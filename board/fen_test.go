@@ -0,0 +1,69 @@
+package board
+
+import "testing"
+
+func TestParseFEN(t *testing.T) {
+	b, color, err := ParseFEN(StartFEN)
+	if err != nil {
+		t.Fatalf("ParseFEN(StartFEN) = %v", err)
+	}
+	if b != NewBoard() || color != White {
+		t.Errorf("ParseFEN(StartFEN) не совпадает с NewBoard()")
+	}
+
+	b, color, err = ParseFEN("4k3/8/8/3p4/8/8/8/R3K3 b - - 12 40")
+	if err != nil {
+		t.Fatalf("ParseFEN = %v", err)
+	}
+	if color != Black {
+		t.Errorf("цвет хода = %v, want Black", color)
+	}
+	for _, sq := range []struct {
+		x, y  int
+		piece Piece
+		color Color
+	}{
+		{7, 4, King, Black}, {4, 3, Pawn, Black}, {0, 0, Rook, White}, {0, 4, King, White},
+	} {
+		if piece, pieceColor, _ := b.GetPiece(sq.x, sq.y); piece != sq.piece || pieceColor != sq.color {
+			t.Errorf("клетка (%d, %d) = %v %v, want %v %v", sq.x, sq.y, piece, pieceColor, sq.piece, sq.color)
+		}
+	}
+}
+
+func TestParseFENErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+	}{
+		{"пустая строка", ""},
+		{"семь горизонталей", "8/8/8/8/8/8/8 w"},
+		{"короткая горизонталь", "7/8/8/8/8/8/8/8 w"},
+		{"длинная горизонталь", "ppppppppp/8/8/8/8/8/8/8 w"},
+		{"неизвестная фигура", "4x3/8/8/8/8/8/8/8 w"},
+		{"цвет хода", "8/8/8/8/8/8/8/8 x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseFEN(tt.fen); err == nil {
+				t.Errorf("ParseFEN(%q): нет ошибки", tt.fen)
+			}
+		})
+	}
+}
+
+func TestHalfmoveClock(t *testing.T) {
+	tests := []struct {
+		fen  string
+		want int
+	}{
+		{"4k3/8/8/8/8/8/8/4K3 w - - 12 40", 12},
+		{"4k3/8/8/8/8/8/8/4K3 w", 0},
+		{"4k3/8/8/8/8/8/8/4K3 w - - x 40", 0},
+	}
+	for _, tt := range tests {
+		if got := HalfmoveClock(tt.fen); got != tt.want {
+			t.Errorf("HalfmoveClock(%q) = %d, want %d", tt.fen, got, tt.want)
+		}
+	}
+}
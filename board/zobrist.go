@@ -0,0 +1,53 @@
+package board
+
+import "math/rand"
+
+// Случайные ключи Zobrist для каждой фигуры каждого цвета на каждой клетке и для хода чёрных.
+// Генератор с фиксированным зерном даёт одинаковые хеши при каждом запуске.
+var (
+	zobristPieces [2][7][64]uint64
+	zobristBlack  uint64
+)
+
+func init() {
+	r := rand.New(rand.NewSource(20250101))
+	for c := range zobristPieces {
+		for p := range zobristPieces[c] {
+			for sq := range zobristPieces[c][p] {
+				zobristPieces[c][p][sq] = r.Uint64()
+			}
+		}
+	}
+	zobristBlack = r.Uint64()
+}
+
+// Hash возвращает хеш Zobrist позиции, в которой ход за color. Права на рокировку
+// и взятие на проходе доска не хранит, поэтому они в хеше не учитываются.
+func (b Board) Hash(color Color) uint64 {
+	var h uint64
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			if sq := b[x][y]; sq.Piece != Empty {
+				h ^= zobristPieces[sq.Color][sq.Piece][x*8+y]
+			}
+		}
+	}
+	if color == Black {
+		h ^= zobristBlack
+	}
+	return h
+}
+
+// PawnHash возвращает хеш Zobrist расположения пешек обоих цветов без остальных фигур:
+// позиции с одинаковой пешечной структурой получают одинаковый хеш
+func (b Board) PawnHash() uint64 {
+	var h uint64
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			if sq := b[x][y]; sq.Piece == Pawn {
+				h ^= zobristPieces[sq.Color][Pawn][x*8+y]
+			}
+		}
+	}
+	return h
+}
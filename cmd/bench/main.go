@@ -0,0 +1,135 @@
+package main
+
+import (
+	"chess-engine/board"
+	"chess-engine/evaluation"
+	"chess-engine/search"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// Набор позиций для замера скорости поиска: дебют, миттельшпиль и эндшпиль
+var benchPositions = []string{
+	board.StartFEN,
+	"r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 2 3",
+	"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+	"r1bq1rk1/pp2bppp/2n1pn2/3p4/2PP4/2N1PN2/PP3PPP/R2QKB1R w KQ - 0 8",
+	"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+	"6k1/5ppp/8/8/8/8/5PPP/3R2K1 w - - 0 1",
+}
+
+// Тактические позиции с лучшими ходами: маты в несколько ходов и выигрыш материала вилкой
+var tacticalPositions = []struct {
+	fen  string
+	best []string
+}{
+	{"r1bqkb1r/pppp1ppp/2n2n2/4p2Q/2B1P3/8/PPPP1PPP/RNB1K1NR w KQkq - 4 4", []string{"h5f7"}},
+	{"4k3/8/8/8/3n4/8/8/R3K3 b - - 0 1", []string{"d4c2"}},
+	{"k7/8/2K5/8/8/8/8/7R w - - 0 1", []string{"c6b6", "c6c7"}},
+	{"6k1/5ppp/8/8/8/8/1q3PPP/3R2K1 w - - 0 1", []string{"d1d8"}},
+	{"4r1k1/5ppp/8/8/8/8/4RPPP/4R1K1 w - - 0 1", []string{"e2e8"}},
+	{"6k1/5ppp/4n3/8/8/8/5PPP/3RR1K1 w - - 0 1", []string{"d1d8"}},
+}
+
+func main() {
+	depth := flag.Int("depth", 4, "глубина поиска")
+	opts := search.DefaultOptions()
+	flag.BoolVar(&opts.NullMove, "nullmove", opts.NullMove, "отсечение нулевым ходом")
+	flag.BoolVar(&opts.LMR, "lmr", opts.LMR, "сокращение глубины поздних ходов")
+	flag.BoolVar(&opts.ReverseFutility, "rfp", opts.ReverseFutility, "обратное отсечение бесперспективных узлов")
+	flag.BoolVar(&opts.Futility, "futility", opts.Futility, "отсечение бесперспективных тихих ходов")
+	flag.BoolVar(&opts.LateMovePruning, "lmp", opts.LateMovePruning, "отсечение поздних тихих ходов")
+	flag.BoolVar(&opts.SEEPruning, "see", opts.SEEPruning, "отсечение проигрывающих взятий по SEE")
+	flag.BoolVar(&opts.CheckExtension, "checkext", opts.CheckExtension, "продление шахов")
+	flag.BoolVar(&opts.SingularExtension, "singular", opts.SingularExtension, "продление единственного хода")
+	flag.BoolVar(&opts.RecaptureExtension, "recapture", opts.RecaptureExtension, "продление взятия в ответ на взятие")
+	flag.BoolVar(&opts.PassedPawnExtension, "passedpawn", opts.PassedPawnExtension, "продление хода пешки на предпоследнюю горизонталь")
+	flag.IntVar(&opts.MaxExtensions, "maxext", opts.MaxExtensions, "наибольшее число продлений на одном пути")
+	flag.StringVar(&opts.TraceFile, "trace", "", "файл трассировки дерева поиска последней позиции (см. cmd/traceview)")
+	flag.IntVar(&opts.TracePly, "traceply", 0, "глубина трассировки от корня")
+	flag.BoolVar(&opts.Deterministic, "deterministic", opts.Deterministic, "воспроизводимый поиск с пустыми таблицами")
+	flag.Int64Var(&opts.Seed, "seed", opts.Seed, "начальное значение случайных чисел воспроизводимого поиска")
+	fen := flag.String("fen", "", "искать только в этой позиции")
+	paramsFile := flag.String("params", "", "JSON с весами оценки (см. cmd/tune); без него — веса по умолчанию")
+	tactics := flag.Bool("tactics", false, "решать тактические позиции вместо замера скорости")
+	flag.Parse()
+	if *fen != "" {
+		benchPositions = []string{*fen}
+	}
+	if *paramsFile != "" {
+		if err := evaluation.UseParamsFile(*paramsFile); err != nil {
+			log.Fatalf("Ошибка загрузки весов оценки: %v", err)
+		}
+	}
+	searcher := search.NewSearcher()
+	searcher.SetOptions(opts)
+	if *tactics {
+		runTactics(searcher, *depth)
+		return
+	}
+
+	var totalNodes, failLows, failHighs int
+	var totalTime time.Duration
+	for i, fen := range benchPositions {
+		b, color, err := board.ParseFEN(fen)
+		if err != nil {
+			log.Fatalf("Ошибка разбора позиции %d: %v", i+1, err)
+		}
+
+		searcher.Clear()
+		res, stats := searcher.FindBestMove(context.Background(), b, color, search.SearchLimits{Depth: *depth})
+		totalNodes += stats.NodesEvaluated
+		totalTime += stats.SearchTime
+		failLows += stats.AspirationFailLows
+		failHighs += stats.AspirationFailHighs
+		fmt.Printf("Позиция %d: вариант %s, оценка %d, узлов %d, время %v\n", i+1, search.FormatPV(res.PV), res.Score, stats.NodesEvaluated, stats.SearchTime.Round(time.Millisecond))
+	}
+
+	nps := 0
+	if totalTime > 0 {
+		nps = int(float64(totalNodes) / totalTime.Seconds())
+	}
+	fmt.Printf("\nВсего узлов: %d\nОбщее время: %v\nУзлов в секунду: %d\n", totalNodes, totalTime.Round(time.Millisecond), nps)
+	fmt.Printf("Перепоиски окна стремления: %d снизу, %d сверху\n", failLows, failHighs)
+}
+
+// runTactics ищет лучший ход в тактических позициях и сообщает, сколько из них решено;
+// если решены не все, программа завершается с кодом 1
+func runTactics(searcher *search.Searcher, depth int) {
+	solved, totalNodes := 0, 0
+	var totalTime time.Duration
+	for i, pos := range tacticalPositions {
+		b, color, err := board.ParseFEN(pos.fen)
+		if err != nil {
+			log.Fatalf("Ошибка разбора позиции %d: %v", i+1, err)
+		}
+
+		searcher.Clear()
+		res, stats := searcher.FindBestMove(context.Background(), b, color, search.SearchLimits{Depth: depth})
+		totalNodes += stats.NodesEvaluated
+		totalTime += stats.SearchTime
+		found := ""
+		if len(res.BestMoves) > 0 {
+			found = res.BestMoves[0].String()
+		}
+		result := "не решена"
+		for _, best := range pos.best {
+			if found == best {
+				result = "решена"
+				solved++
+				break
+			}
+		}
+		fmt.Printf("Позиция %d: ход %s, ожидался %s — %s, узлов %d, время %v\n", i+1, found, strings.Join(pos.best, " или "), result, stats.NodesEvaluated, stats.SearchTime.Round(time.Millisecond))
+	}
+	fmt.Printf("\nРешено %d из %d, всего узлов: %d, общее время: %v\n", solved, len(tacticalPositions), totalNodes, totalTime.Round(time.Millisecond))
+	if solved < len(tacticalPositions) {
+		// Ненулевой код выхода позволяет использовать набор как проверку
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"chess-engine/search"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Описания причин досрочного завершения узла из search.TraceNode.Cutoff
+var cutoffNames = map[string]string{
+	"beta":      "отсечение по beta",
+	"tt":        "оценка из таблицы",
+	"null":      "отсечение нулевым ходом",
+	"rfp":       "обратное отсечение",
+	"standpat":  "статическая оценка не ниже beta",
+	"draw":      "ничья",
+	"mate":      "мат",
+	"stalemate": "пат",
+	"stopped":   "поиск прерван",
+}
+
+func main() {
+	file := flag.String("file", "trace.jsonl", "файл трассировки")
+	rootMove := flag.String("move", "", "ход в корне, дерево которого вывести; без него — список ходов в корне")
+	iteration := flag.Int("iter", 0, "итерация итеративного углубления; 0 — последняя")
+	maxPly := flag.Int("ply", 0, "наибольшая глубина выводимых узлов; 0 — все записанные")
+	flag.Parse()
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Ошибка открытия трассировки: %v", err)
+	}
+	defer f.Close()
+	nodes, err := search.ReadTrace(f)
+	if err != nil {
+		log.Fatalf("Ошибка чтения трассировки: %v", err)
+	}
+	if len(nodes) == 0 {
+		log.Fatal("Трассировка пуста")
+	}
+
+	if *iteration == 0 {
+		for _, node := range nodes {
+			*iteration = max(*iteration, node.Iteration)
+		}
+	}
+
+	printed := 0
+	for _, node := range nodes {
+		if node.Iteration != *iteration || (*maxPly > 0 && node.Ply > *maxPly) {
+			continue
+		}
+		if *rootMove == "" {
+			// Список ходов в корне: оценка и окно с точки зрения стороны, которая ходит в корне
+			if node.Ply == 1 {
+				node.Score, node.Alpha, node.Beta = -node.Score, -node.Beta, -node.Alpha
+				fmt.Printf("%-6s оценка %d, %s\n", node.Path[0], node.Score, describe(node))
+				printed++
+			}
+			continue
+		}
+		if node.Path[0] != *rootMove {
+			continue
+		}
+		fmt.Printf("%s%-6s %s, оценка %d, %s\n", strings.Repeat("  ", node.Ply-1), node.Path[node.Ply-1], depthName(node), node.Score, describe(node))
+		printed++
+	}
+	if printed == 0 {
+		log.Printf("В итерации %d нет подходящих узлов", *iteration)
+	}
+}
+
+// depthName описывает оставшуюся глубину узла
+func depthName(node search.TraceNode) string {
+	if node.Quiescence {
+		return fmt.Sprintf("взятия %d", node.Depth)
+	}
+	return fmt.Sprintf("глубина %d", node.Depth)
+}
+
+// describe описывает окно поиска, попадание в таблицу и причину завершения узла
+func describe(node search.TraceNode) string {
+	s := fmt.Sprintf("окно [%d, %d]", node.Alpha, node.Beta)
+	if node.TTHit {
+		s += ", есть в таблице"
+	}
+	if node.Cutoff != "" {
+		s += ", " + cutoffNames[node.Cutoff]
+	}
+	return s
+}
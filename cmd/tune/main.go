@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"chess-engine/board"
+	"chess-engine/evaluation"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// position — позиция из обучающей выборки и результат партии для белых: 1, 0.5 или 0
+type position struct {
+	board  board.Board
+	result float64
+}
+
+// Обозначения результата партии в строке выборки
+var results = map[string]float64{
+	"1-0": 1, "0-1": 0, "1/2-1/2": 0.5,
+	"[1.0]": 1, "[0.0]": 0, "[0.5]": 0.5,
+	"[1]": 1, "[0]": 0,
+}
+
+// Настройка весов оценки методом Texel: веса по одному сдвигаются на step в обе стороны,
+// пока среднеквадратичная ошибка предсказания результатов партий по оценке уменьшается.
+// Позиции в выборке должны быть спокойными — без взятий и шахов на следующем ходу.
+func main() {
+	data := flag.String("data", "", "файл выборки: в каждой строке FEN и результат партии (1-0, 0-1, 1/2-1/2 или [1.0], [0.0], [0.5])")
+	start := flag.String("params", "", "JSON с начальными весами; без него — веса по умолчанию")
+	out := flag.String("out", "eval_params.json", "файл для настроенных весов")
+	iterations := flag.Int("iterations", 100, "наибольшее число проходов по весам")
+	step := flag.Int("step", 1, "шаг изменения веса")
+	k := flag.Float64("k", 0, "масштаб сигмоиды; 0 — подобрать по выборке")
+	threads := flag.Int("threads", runtime.NumCPU(), "число потоков оценки")
+	flag.Parse()
+	if *data == "" {
+		log.Fatal("Не задан файл выборки (-data)")
+	}
+
+	positions, err := loadPositions(*data)
+	if err != nil {
+		log.Fatalf("Ошибка чтения выборки: %v", err)
+	}
+	log.Printf("Позиций: %d", len(positions))
+
+	params := evaluation.DefaultParams()
+	if *start != "" {
+		if params, err = evaluation.LoadParams(*start); err != nil {
+			log.Fatalf("Ошибка загрузки весов: %v", err)
+		}
+	}
+	evaluation.SetParams(params)
+
+	t := &tuner{positions: positions, threads: *threads}
+	if *k == 0 {
+		*k = t.findK()
+	}
+	t.k = *k
+	best := t.meanError()
+	log.Printf("K = %.4f, начальная ошибка %.6f", t.k, best)
+
+	weights := params.Weights()
+	for pass := 1; pass <= *iterations; pass++ {
+		begin := time.Now()
+		improved := 0
+		for _, w := range weights {
+			for _, delta := range []int{*step, -*step} {
+				*w += delta
+				// Шаг, после которого оценка некорректна (например, нулевой делитель), пропускается
+				if params.Validate() != nil {
+					*w -= delta
+					continue
+				}
+				evaluation.SetParams(params)
+				if e := t.meanError(); e < best {
+					best = e
+					improved++
+					break
+				}
+				*w -= delta
+				evaluation.SetParams(params)
+			}
+		}
+		log.Printf("Проход %d: улучшено весов %d, ошибка %.6f, %v", pass, improved, best, time.Since(begin).Round(time.Millisecond))
+		if err := writeParams(*out, params); err != nil {
+			log.Fatalf("Ошибка записи весов: %v", err)
+		}
+		if improved == 0 {
+			break
+		}
+	}
+	fmt.Printf("Настроенные веса записаны в %s, ошибка %.6f\n", *out, best)
+}
+
+// loadPositions читает выборку: FEN — всё до обозначения результата в строке
+func loadPositions(path string) ([]position, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var positions []position
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(strings.NewReplacer(`"`, " ", ";", " ").Replace(scanner.Text()))
+		if len(fields) == 0 {
+			continue
+		}
+		found := false
+		for i, field := range fields {
+			result, ok := results[field]
+			if !ok {
+				continue
+			}
+			b, _, err := board.ParseFEN(strings.Join(fields[:i], " "))
+			if err != nil {
+				return nil, fmt.Errorf("строка %d: %v", line, err)
+			}
+			positions = append(positions, position{board: b, result: result})
+			found = true
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("строка %d: нет результата партии", line)
+		}
+	}
+	return positions, scanner.Err()
+}
+
+// tuner считает ошибку предсказания результатов выборки по текущим весам оценки
+type tuner struct {
+	positions []position
+	threads   int
+	k         float64
+	evals     []int // Оценки позиций, посчитанные последним вызовом evaluate
+}
+
+// evaluate оценивает все позиции выборки в нескольких потоках
+func (t *tuner) evaluate() {
+	if t.evals == nil {
+		t.evals = make([]int, len(t.positions))
+	}
+	var wg sync.WaitGroup
+	chunk := (len(t.positions) + t.threads - 1) / t.threads
+	for from := 0; from < len(t.positions); from += chunk {
+		to := min(from+chunk, len(t.positions))
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			for i := from; i < to; i++ {
+				t.evals[i] = evaluation.Evaluate(t.positions[i].board)
+			}
+		}(from, to)
+	}
+	wg.Wait()
+}
+
+// errorFor возвращает среднеквадратичную ошибку последних оценок при масштабе k
+func (t *tuner) errorFor(k float64) float64 {
+	sum := 0.0
+	for i, p := range t.positions {
+		d := p.result - sigmoid(k, t.evals[i])
+		sum += d * d
+	}
+	return sum / float64(len(t.positions))
+}
+
+// meanError оценивает выборку текущими весами и возвращает ошибку
+func (t *tuner) meanError() float64 {
+	t.evaluate()
+	return t.errorFor(t.k)
+}
+
+// findK подбирает масштаб сигмоиды, при котором ошибка начальных весов наименьшая:
+// сначала грубо, затем всё мельче вокруг лучшего значения
+func (t *tuner) findK() float64 {
+	t.evaluate()
+	best, bestErr := 1.0, t.errorFor(1)
+	for step := 0.5; step >= 0.0001; step /= 10 {
+		from := best
+		for k := max(from-10*step, step); k <= from+10*step; k += step {
+			if e := t.errorFor(k); e < bestErr {
+				best, bestErr = k, e
+			}
+		}
+	}
+	return best
+}
+
+// sigmoid переводит оценку в сантипешках в ожидаемый результат партии для белых
+func sigmoid(k float64, eval int) float64 {
+	return 1 / (1 + math.Pow(10, -k*float64(eval)/400))
+}
+
+// writeParams записывает веса params в path, если они проходят проверку Validate
+func writeParams(path string, params evaluation.Params) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"chess-engine/uci"
+	"os"
+)
+
+func main() {
+	uci.Run(os.Stdin, os.Stdout)
+}
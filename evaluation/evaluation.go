@@ -3,10 +3,10 @@ package evaluation
 import (
 	"chess-engine/board"
 	"chess-engine/move"
-	"chess-engine/util"
-	"math"
 )
 
+// PieceValues — стоимость фигур для размена (SEE) и упорядочивания ходов;
+// материал в оценке берётся из Params.PieceValues
 var PieceValues = map[board.Piece]int{
 	board.Pawn:   100,
 	board.Knight: 320,
@@ -16,129 +16,82 @@ var PieceValues = map[board.Piece]int{
 	board.King:   20000,
 }
 
-// Бонусы за контроль центра для пешек и легких фигур
-var centerBonus = [8][8]int{
-	{0, 0, 0, 0, 0, 0, 0, 0},
-	{0, 0, 0, 0, 0, 0, 0, 0},
-	{0, 0, 5, 10, 10, 5, 0, 0},
-	{0, 5, 10, 20, 20, 10, 5, 0},
-	{0, 5, 10, 20, 20, 10, 5, 0},
-	{0, 0, 5, 10, 10, 5, 0, 0},
-	{0, 0, 0, 0, 0, 0, 0, 0},
-	{0, 0, 0, 0, 0, 0, 0, 0},
+// checkPenalty — штраф стороне, король которой под шахом
+const checkPenalty = 50
+
+// Score — вклад в оценку для миттельшпиля и эндшпиля
+type Score struct {
+	Mg, Eg int
+}
+
+func (s *Score) add(mg, eg int) {
+	s.Mg += mg
+	s.Eg += eg
 }
 
+// Признаки оценки в порядке вывода в Trace
+const (
+	termMaterial = iota
+	termPST
+	termPawns
+	termMobility
+	termKingSafety
+	termThreats
+	termCheck
+	termCount
+)
+
+var termNames = [termCount]string{"Материал", "Фигура-клетка", "Пешки", "Подвижность", "Безопасность короля", "Угрозы", "Шах"}
+
+// Evaluate возвращает оценку позиции с точки зрения белых: материал и смешанные
+// по фазе партии таблицы фигура-клетка, пешечная структура, подвижность фигур
+// безопасность королей и висящие фигуры
 func Evaluate(b board.Board) int {
+	terms, phase := evaluateTerms(b)
 	score := 0
+	var positional Score
+	for i, term := range terms {
+		mg, eg := term[board.White].Mg-term[board.Black].Mg, term[board.White].Eg-term[board.Black].Eg
+		if i == termMaterial || i == termCheck {
+			// Материал и штраф за шах одинаковы в обеих частях и от фазы не зависят
+			score += mg
+			continue
+		}
+		positional.add(mg, eg)
+	}
+	// Позиционные бонусы плавно переходят от миттельшпиля к эндшпилю по мере размена фигур
+	return score + taper(positional.Mg, positional.Eg, phase)
+}
 
-	// Битовые маски для подсчета фигур
-	var whitePieces, blackPieces uint64
+// evaluateTerms вычисляет каждый признак оценки для каждой стороны и фазу партии
+func evaluateTerms(b board.Board) (terms [termCount][2]Score, phase int) {
+	p := params.Load()
+	var kings [2][2]int
 	for i := 0; i < 8; i++ {
 		for j := 0; j < 8; j++ {
 			piece, color, _ := b.GetPiece(i, j)
 			if piece == board.Empty {
 				continue
 			}
-			// Индекс клетки в 64-битной маске: i*8 + j
-			bitPos := uint(i*8 + j)
-			if color == board.White {
-				whitePieces |= (1 << bitPos)
-				score += PieceValues[piece]
-				// Бонус за центр для пешек и легких фигур
-				if piece == board.Pawn || piece == board.Knight || piece == board.Bishop {
-					score += centerBonus[i][j]
-				}
+			if piece == board.King {
+				// Короли есть у обеих сторон всегда, поэтому в материал не входят
+				kings[color] = [2]int{i, j}
 			} else {
-				blackPieces |= (1 << bitPos)
-				score -= PieceValues[piece]
-				// Штраф за центр для черных (отзеркаливаем доску)
-				if piece == board.Pawn || piece == board.Knight || piece == board.Bishop {
-					score -= centerBonus[7-i][j]
-				}
-			}
-		}
-	}
-
-	// Подсчет активных фигур
-	whiteCount := util.PopCount(whitePieces)
-	blackCount := util.PopCount(blackPieces)
-
-	// Бонус за мобильность
-	score += (whiteCount - blackCount) * 10
-
-	// Штраф за короля под шахом
-	if move.IsKingInCheck(b, board.White) {
-		score -= 50
-	}
-	if move.IsKingInCheck(b, board.Black) {
-		score += 50
-	}
-
-	// Безопасность короля
-	score += kingSafety(b, board.White)
-	score -= kingSafety(b, board.Black)
-
-	return score
-}
-
-// kingSafety оценивает безопасность короля
-func kingSafety(b board.Board, color board.Color) int {
-	safetyScore := 0
-
-	// Находим позицию короля
-	var kingX, kingY int
-	for x := 0; x < 8; x++ {
-		for y := 0; y < 8; y++ {
-			piece, pieceColor, _ := b.GetPiece(x, y)
-			if piece == board.King && pieceColor == color {
-				kingX, kingY = x, y
-				break
+				terms[termMaterial][color].add(p.PieceValues[piece], p.PieceValues[piece])
 			}
+			terms[termPST][color].add(pieceSquare(p, piece, color, i, j))
 		}
 	}
 
-	// Проверяем близость фигур противника
-	opponentColor := board.Black
-	if color == board.Black {
-		opponentColor = board.White
-	}
-
-	for x := 0; x < 8; x++ {
-		for y := 0; y < 8; y++ {
-			piece, pieceColor, _ := b.GetPiece(x, y)
-			if piece != board.Empty && pieceColor == opponentColor {
-				// Вычисляем расстояние до короля
-				distance := int(math.Sqrt(float64((x-kingX)*(x-kingX) + (y-kingY)*(y-kingY))))
-				if distance > 0 && distance <= 3 { // Учитываем только близкие фигуры
-					switch piece {
-					case board.Pawn:
-						safetyScore -= 5 / distance
-					case board.Knight:
-						safetyScore -= 10 / distance
-					case board.Bishop:
-						safetyScore -= 15 / distance
-					case board.Rook:
-						safetyScore -= 20 / distance
-					case board.Queen:
-						safetyScore -= 30 / distance
-					}
-				}
-			}
+	pawns := probePawns(b, p)
+	terms[termPawns] = evaluatePawns(b, p, pawns, kings)
+	terms[termMobility] = evaluateMobility(b, p, pawns.pawns)
+	terms[termKingSafety] = evaluateKingSafety(b, p, kings, pawns.pawns)
+	terms[termThreats] = evaluateThreats(b, p)
+	for _, color := range []board.Color{board.White, board.Black} {
+		if move.IsKingInCheck(b, color) {
+			terms[termCheck][color].add(-p.CheckPenalty, -p.CheckPenalty)
 		}
 	}
-
-	// Бонус за пешки рядом с королём (защита)
-	for dx := -1; dx <= 1; dx++ {
-		for dy := -1; dy <= 1; dy++ {
-			nx, ny := kingX+dx, kingY+dy
-			if nx >= 0 && nx < 8 && ny >= 0 && ny < 8 {
-				piece, pieceColor, _ := b.GetPiece(nx, ny)
-				if piece == board.Pawn && pieceColor == color {
-					safetyScore += 10
-				}
-			}
-		}
-	}
-
-	return safetyScore
+	return terms, GamePhase(b)
 }
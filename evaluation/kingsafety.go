@@ -0,0 +1,188 @@
+package evaluation
+
+import (
+	"chess-engine/board"
+	"chess-engine/util"
+)
+
+// Опасность для короля в условных единицах: вес каждой фигуры соперника, которая бьёт
+// зону короля, вес каждой битой клетки зоны и вес безопасного шаха, который фигура может дать
+var (
+	kingAttackerWeight = [7]int{board.Knight: 20, board.Bishop: 20, board.Rook: 40, board.Queen: 80}
+	safeCheckWeight    = [7]int{board.Knight: 60, board.Bishop: 35, board.Rook: 60, board.Queen: 45}
+)
+
+const (
+	zoneAttackWeight = 8
+	kingDangerScale  = 512 // Штраф в миттельшпиле — квадрат опасности, делённый на kingDangerScale
+	kingDangerEg     = 8   // Штраф в эндшпиле — опасность, делённая на kingDangerEg
+	maxKingDanger    = 500 // Наибольший штраф за опасность
+)
+
+// Пешечный щит и штурм по каждой из трёх вертикалей у короля в миттельшпиле.
+// Индекс — на сколько горизонталей ближайшая пешка впереди короля, плюс один;
+// 0 — пешки на вертикали впереди короля нет.
+var (
+	pawnShield = [8]int{-20, 10, 15, 8, 2, 0, 0, 0}
+	pawnStorm  = [8]int{0, 0, -25, -15, -8, -3, 0, 0}
+)
+
+const (
+	openFilePenalty     = -25 // Вертикаль у короля без пешек
+	semiOpenFilePenalty = -12 // Вертикаль у короля без своих пешек
+)
+
+// evaluateKingSafety возвращает оценку безопасности короля каждого цвета; чем выше,
+// тем безопаснее, с весами p. kings — клетки королей, pawns — пешки каждого цвета.
+func evaluateKingSafety(b board.Board, p *Params, kings [2][2]int, pawns [2]uint64) [2]Score {
+	var score [2]Score
+	var zone, defended, pieces [2]uint64
+	var checks, reach [2][7]uint64 // Для короля каждого цвета: клетки шахов и клетки, куда идут фигуры соперника
+	var attackers, danger [2]int
+	for _, color := range []board.Color{board.White, board.Black} {
+		kx, ky := kings[color][0], kings[color][1]
+		forward := 1
+		if color == board.Black {
+			forward = -1
+		}
+		// Зона короля — соседние с ним клетки и ещё одна горизонталь в сторону соперника
+		zone[color] = kingMoves(kx, ky) | squareBit(kx, ky)
+		for dy := -1; dy <= 1; dy++ {
+			zone[color] |= squareBit(kx+2*forward, ky+dy)
+		}
+		// На защищённых клетках шах соперника не безопасен; фигуры добавятся ниже
+		defended[color] = pawnAttacks(pawns[color], color) | kingMoves(kx, ky)
+		checks[color][board.Knight] = knightMoves(kx, ky)
+		checks[color][board.Bishop] = sliderAttacks(b, kx, ky, diagonalDirections[:])
+		checks[color][board.Rook] = sliderAttacks(b, kx, ky, straightDirections[:])
+		checks[color][board.Queen] = checks[color][board.Bishop] | checks[color][board.Rook]
+	}
+
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			piece, color, _ := b.GetPiece(x, y)
+			if piece == board.Empty {
+				continue
+			}
+			pieces[color] |= squareBit(x, y)
+			// Пешки учтены в defended выше, а король в атаке не участвует
+			if piece < board.Knight || piece > board.Queen {
+				continue
+			}
+			attacks := pieceAttacks(b, x, y, piece)
+			defended[color] |= attacks
+			enemy := opposite(color)
+			if hits := attacks & zone[enemy]; hits != 0 {
+				attackers[enemy]++
+				danger[enemy] += p.KingAttackerWeight[piece] + p.ZoneAttackWeight*util.PopCount(hits)
+			}
+			reach[enemy][piece] |= attacks
+		}
+	}
+
+	for _, color := range []board.Color{board.White, board.Black} {
+		enemy := opposite(color)
+		// Безопасный шах — с клетки без фигуры соперника, которую не защищает сторона короля
+		for _, piece := range []board.Piece{board.Knight, board.Bishop, board.Rook, board.Queen} {
+			if reach[color][piece]&checks[color][piece]&^pieces[enemy]&^defended[color] != 0 {
+				danger[color] += p.SafeCheckWeight[piece]
+			}
+		}
+		// Одна фигура редко может поставить мат, поэтому атака считается с двух фигур
+		if attackers[color] >= 2 {
+			score[color].add(-min(danger[color]*danger[color]/p.KingDangerScale, p.MaxKingDanger), -danger[color]/p.KingDangerEg)
+		}
+
+		// Пешечный щит, штурм и открытые вертикали у короля важны только в миттельшпиле
+		kx, ky := kings[color][0], kings[color][1]
+		for y := max(0, ky-1); y <= min(7, ky+1); y++ {
+			own := nearestPawn(pawns[color], color, kx, y)
+			storm := nearestPawn(pawns[enemy], color, kx, y)
+			shelter := p.PawnShield[own]
+			if storm != 0 {
+				penalty := p.PawnStorm[storm]
+				if own != 0 && own == storm-1 {
+					// Пешку соперника останавливает своя пешка прямо перед ней
+					penalty /= 2
+				}
+				shelter += penalty
+			}
+			switch {
+			case own == 0 && storm == 0:
+				shelter += p.OpenFile
+			case own == 0:
+				shelter += p.SemiOpenFile
+			}
+			score[color].add(shelter, 0)
+		}
+	}
+	return score
+}
+
+// nearestPawn ищет на вертикали y ближайшую пешку из pawns не позади горизонтали kx
+// короля стороны color и возвращает, на сколько горизонталей она впереди короля, плюс один;
+// 0 — такой пешки нет
+func nearestPawn(pawns uint64, color board.Color, kx, y int) int {
+	for rank := relativeRank(color, kx); rank < 8; rank++ {
+		if hasPawn(pawns, relativeRank(color, rank), y) {
+			return min(rank-relativeRank(color, kx)+1, 7)
+		}
+	}
+	return 0
+}
+
+// pieceAttacks возвращает клетки, которые бьёт конь, слон, ладья, ферзь или король на клетке (x, y)
+func pieceAttacks(b board.Board, x, y int, piece board.Piece) uint64 {
+	switch piece {
+	case board.Knight:
+		return knightMoves(x, y)
+	case board.Bishop:
+		return sliderAttacks(b, x, y, diagonalDirections[:])
+	case board.Rook:
+		return sliderAttacks(b, x, y, straightDirections[:])
+	case board.Queen:
+		return sliderAttacks(b, x, y, diagonalDirections[:]) | sliderAttacks(b, x, y, straightDirections[:])
+	case board.King:
+		return kingMoves(x, y)
+	}
+	return 0
+}
+
+// sliderAttacks возвращает клетки, которые дальнобойная фигура на клетке (x, y) бьёт
+// по направлениям directions, включая первую занятую клетку на каждом луче
+func sliderAttacks(b board.Board, x, y int, directions [][2]int) uint64 {
+	var attacks uint64
+	for _, d := range directions {
+		for nx, ny := x+d[0], y+d[1]; nx >= 0 && nx < 8 && ny >= 0 && ny < 8; nx, ny = nx+d[0], ny+d[1] {
+			attacks |= squareBit(nx, ny)
+			if !b.IsEmpty(nx, ny) {
+				break
+			}
+		}
+	}
+	return attacks
+}
+
+func knightMoves(x, y int) uint64 {
+	var attacks uint64
+	for _, o := range knightOffsets {
+		attacks |= squareBit(x+o[0], y+o[1])
+	}
+	return attacks
+}
+
+func kingMoves(x, y int) uint64 {
+	var attacks uint64
+	for _, o := range kingOffsets {
+		attacks |= squareBit(x+o[0], y+o[1])
+	}
+	return attacks
+}
+
+// squareBit возвращает маску клетки (x, y) или 0 для клетки вне доски
+func squareBit(x, y int) uint64 {
+	if x < 0 || x >= 8 || y < 0 || y >= 8 {
+		return 0
+	}
+	return 1 << uint(x*8+y)
+}
@@ -0,0 +1,51 @@
+package evaluation
+
+import "testing"
+
+func TestKingDanger(t *testing.T) {
+	// К рокированному королю чёрных по одной подключаются ферзь, конь и ладья
+	fens := []string{
+		"6k1/5ppp/8/8/8/8/8/4K3 w - - 0 1",
+		"6k1/5ppp/8/7Q/8/8/8/4K3 w - - 0 1",
+		"6k1/5ppp/8/6NQ/8/8/8/4K3 w - - 0 1",
+		"6k1/5ppp/R7/6NQ/8/8/8/4K3 w - - 0 1",
+	}
+	var safety [4]Score
+	for i, fen := range fens {
+		safety[i] = traceTerm(t, fen, termKingSafety).Black
+	}
+	// Одна фигура у короля не считается атакой
+	if safety[1] != safety[0] {
+		t.Errorf("безопасность с одним нападающим %+v, без нападающих %+v; want равны", safety[1], safety[0])
+	}
+	for i := 2; i < len(safety); i++ {
+		if safety[i].Mg >= safety[i-1].Mg || safety[i].Eg >= safety[i-1].Eg {
+			t.Errorf("безопасность с %d нападающими %+v, с %d — %+v; want меньше", i, safety[i], i-1, safety[i-1])
+		}
+	}
+}
+
+func TestPawnShelter(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		want int // Оценка щита короля белых в миттельшпиле
+	}{
+		// Пешки f2, g2 и h2 прямо перед королём
+		{"полный щит", "4k3/8/8/8/8/8/5PPP/6K1 w - - 0 1", 3 * pawnShield[2]},
+		// Пешка g2 ушла на g3, h-вертикаль без пешек
+		{"ослабленный щит", "4k3/8/8/8/8/6P1/5P2/6K1 w - - 0 1", pawnShield[2] + pawnShield[3] + pawnShield[0] + openFilePenalty},
+		// Перед пешкой h2 стоит пешка соперника h3
+		{"штурм", "4k3/8/8/8/8/7p/5PPP/6K1 w - - 0 1", 3*pawnShield[2] + pawnStorm[3]/2},
+		// На h-вертикали только пешка соперника h4
+		{"полуоткрытая вертикаль", "4k3/8/8/8/7p/8/5PP1/6K1 w - - 0 1",
+			2*pawnShield[2] + pawnShield[0] + pawnStorm[4] + semiOpenFilePenalty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := traceTerm(t, tt.fen, termKingSafety).White; got.Mg != tt.want || got.Eg != 0 {
+				t.Errorf("безопасность короля белых %+v, want {Mg:%d Eg:0}", got, tt.want)
+			}
+		})
+	}
+}
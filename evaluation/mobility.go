@@ -0,0 +1,104 @@
+package evaluation
+
+import "chess-engine/board"
+
+// Бонусы подвижности по числу безопасных клеток, которые бьёт фигура: первые клетки
+// важнее последующих, а запертая фигура получает штраф
+var (
+	knightMobilityMg = []int{-35, -25, -10, -2, 4, 10, 15, 20, 25}
+	knightMobilityEg = []int{-40, -30, -15, -5, 5, 12, 17, 20, 22}
+	bishopMobilityMg = []int{-25, -12, 0, 6, 12, 18, 22, 26, 29, 32, 34, 36, 38, 40}
+	bishopMobilityEg = []int{-30, -15, -3, 6, 12, 18, 24, 28, 32, 35, 37, 39, 41, 43}
+	rookMobilityMg   = []int{-15, -10, -5, -2, 0, 3, 6, 9, 11, 13, 15, 16, 17, 18, 19}
+	rookMobilityEg   = []int{-40, -20, -5, 5, 12, 18, 25, 32, 38, 43, 47, 50, 52, 54, 56}
+	queenMobilityMg  = []int{-15, -10, -6, -3, 0, 2, 4, 6, 8, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 20, 21, 21, 22, 22, 23, 23, 24}
+	queenMobilityEg  = []int{-25, -15, -8, -2, 3, 7, 11, 15, 18, 21, 24, 27, 29, 31, 33, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44, 45, 46, 47}
+)
+
+// evaluateMobility возвращает бонусы подвижности коней, слонов, ладей и ферзей каждого
+// цвета по таблицам p. pawns — пешки каждого цвета из pawnEntry. Безопасной считается клетка
+// без своей фигуры, которую не бьёт пешка соперника.
+func evaluateMobility(b board.Board, p *Params, pawns [2]uint64) [2]Score {
+	var score [2]Score
+	// Для каждой стороны — клетки, которые бьют пешки соперника
+	attacked := [2]uint64{pawnAttacks(pawns[board.Black], board.Black), pawnAttacks(pawns[board.White], board.White)}
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			piece, color, _ := b.GetPiece(x, y)
+			var tableMg, tableEg []int
+			var moves int
+			switch piece {
+			case board.Knight:
+				tableMg, tableEg = p.KnightMobilityMg[:], p.KnightMobilityEg[:]
+				for _, o := range knightOffsets {
+					if safeSquare(b, x+o[0], y+o[1], color, attacked[color]) {
+						moves++
+					}
+				}
+			case board.Bishop:
+				tableMg, tableEg = p.BishopMobilityMg[:], p.BishopMobilityEg[:]
+				moves = sliderMobility(b, x, y, color, attacked[color], diagonalDirections[:])
+			case board.Rook:
+				tableMg, tableEg = p.RookMobilityMg[:], p.RookMobilityEg[:]
+				moves = sliderMobility(b, x, y, color, attacked[color], straightDirections[:])
+			case board.Queen:
+				tableMg, tableEg = p.QueenMobilityMg[:], p.QueenMobilityEg[:]
+				moves = sliderMobility(b, x, y, color, attacked[color], diagonalDirections[:]) +
+					sliderMobility(b, x, y, color, attacked[color], straightDirections[:])
+			default:
+				continue
+			}
+
+			score[color].add(tableMg[moves], tableEg[moves])
+		}
+	}
+	return score
+}
+
+// sliderMobility считает безопасные клетки, которые дальнобойная фигура стороны color
+// на клетке (x, y) бьёт по направлениям directions
+func sliderMobility(b board.Board, x, y int, color board.Color, attacked uint64, directions [][2]int) int {
+	moves := 0
+	for _, d := range directions {
+		for nx, ny := x+d[0], y+d[1]; nx >= 0 && nx < 8 && ny >= 0 && ny < 8; nx, ny = nx+d[0], ny+d[1] {
+			if safeSquare(b, nx, ny, color, attacked) {
+				moves++
+			}
+			if !b.IsEmpty(nx, ny) {
+				break
+			}
+		}
+	}
+	return moves
+}
+
+// safeSquare проверяет, что на клетку (x, y) может встать фигура стороны color:
+// клетка на доске, не занята своей фигурой и не входит в attacked
+func safeSquare(b board.Board, x, y int, color board.Color, attacked uint64) bool {
+	if x < 0 || x >= 8 || y < 0 || y >= 8 || attacked&(1<<uint(x*8+y)) != 0 {
+		return false
+	}
+	piece, pieceColor, _ := b.GetPiece(x, y)
+	return piece == board.Empty || pieceColor != color
+}
+
+// pawnAttacks возвращает клетки, которые бьют пешки pawns стороны color
+func pawnAttacks(pawns uint64, color board.Color) uint64 {
+	dir := 1
+	if color == board.Black {
+		dir = -1
+	}
+	var attacks uint64
+	for sq := 0; sq < 64; sq++ {
+		if pawns&(1<<uint(sq)) == 0 {
+			continue
+		}
+		x, y := sq/8+dir, sq%8
+		for _, ay := range []int{y - 1, y + 1} {
+			if x >= 0 && x < 8 && ay >= 0 && ay < 8 {
+				attacks |= 1 << uint(x*8+ay)
+			}
+		}
+	}
+	return attacks
+}
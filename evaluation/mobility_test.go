@@ -0,0 +1,33 @@
+package evaluation
+
+import "testing"
+
+func TestMobility(t *testing.T) {
+	tests := []struct {
+		name   string
+		fen    string
+		mg, eg []int
+		moves  int
+	}{
+		// Конь в центре бьёт все восемь клеток
+		{"свободный конь", "4k3/8/8/8/3N4/8/8/4K3 w - - 0 1", knightMobilityMg, knightMobilityEg, 8},
+		// Пешка d7 бьёт c6 и e6: эти клетки не безопасны
+		{"клетки под пешкой соперника", "4k3/3p4/8/8/3N4/8/8/4K3 w - - 0 1", knightMobilityMg, knightMobilityEg, 6},
+		// Клетку b5 занимает своя пешка
+		{"своя фигура", "4k3/8/8/1P6/3N4/8/8/4K3 w - - 0 1", knightMobilityMg, knightMobilityEg, 7},
+		// Ладья a1: вертикаль до a8 и b1–d1 до своего короля, клетка a2 под пешкой b3 не считается,
+		// но ладья бьёт сквозь неё дальше
+		{"ладья", "4k3/8/8/8/8/1p6/8/R3K3 w - - 0 1", rookMobilityMg, rookMobilityEg, 9},
+		// Слон c1 бьёт b2, a3 и d2–h6 до фигуры соперника, которая считается, а дальше — нет
+		{"слон", "4k3/8/7n/8/8/8/8/2B1K3 w - - 0 1", bishopMobilityMg, bishopMobilityEg, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := traceTerm(t, tt.fen, termMobility)
+			want := Score{tt.mg[tt.moves], tt.eg[tt.moves]}
+			if term.White != want {
+				t.Errorf("подвижность белых %+v, want %+v (%d клеток)", term.White, want, tt.moves)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package evaluation
+
+import (
+	"chess-engine/board"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// Params — все веса оценки позиции. Значения по умолчанию подобраны вручную;
+// cmd/tune настраивает их по партиям и сохраняет в JSON, который читает LoadParams.
+type Params struct {
+	// Стоимость фигур в материале по board.Piece; король не учитывается. SEE и упорядочивание
+	// ходов пользуются постоянными PieceValues, чтобы размены не зависели от настройки.
+	PieceValues [7]int
+	MgPST       [7][8][8]int // Таблицы фигура-клетка для белых, см. mgPST
+	EgPST       [7][8][8]int
+
+	Doubled              Score
+	Isolated             Score
+	Backward             Score
+	Connected            [8]int // По горизонтали от своего края доски
+	PassedMg             [8]int
+	PassedEg             [8]int
+	BlockedPassedDivisor int `tune:"-"`
+	OwnKingDistance      int
+	EnemyKingDistance    int
+
+	KnightMobilityMg [9]int
+	KnightMobilityEg [9]int
+	BishopMobilityMg [14]int
+	BishopMobilityEg [14]int
+	RookMobilityMg   [15]int
+	RookMobilityEg   [15]int
+	QueenMobilityMg  [28]int
+	QueenMobilityEg  [28]int
+
+	KingAttackerWeight [7]int
+	SafeCheckWeight    [7]int
+	ZoneAttackWeight   int
+	KingDangerScale    int `tune:"-"`
+	KingDangerEg       int `tune:"-"`
+	MaxKingDanger      int
+	PawnShield         [8]int
+	PawnStorm          [8]int
+	OpenFile           int
+	SemiOpenFile       int
+
+	Hanging      Score // Проценты выигрыша соперника на висящей фигуре, см. evaluateThreats
+	CheckPenalty int
+}
+
+// DefaultParams возвращает веса оценки, подобранные вручную
+func DefaultParams() Params {
+	p := Params{
+		MgPST: mgPST,
+		EgPST: egPST,
+
+		Doubled:              Score{doubledMg, doubledEg},
+		Isolated:             Score{isolatedMg, isolatedEg},
+		Backward:             Score{backwardMg, backwardEg},
+		Connected:            connectedBonus,
+		PassedMg:             passedMg,
+		PassedEg:             passedEg,
+		BlockedPassedDivisor: blockedPassedDivisor,
+		OwnKingDistance:      ownKingDistance,
+		EnemyKingDistance:    enemyKingDistance,
+
+		KingAttackerWeight: kingAttackerWeight,
+		SafeCheckWeight:    safeCheckWeight,
+		ZoneAttackWeight:   zoneAttackWeight,
+		KingDangerScale:    kingDangerScale,
+		KingDangerEg:       kingDangerEg,
+		MaxKingDanger:      maxKingDanger,
+		PawnShield:         pawnShield,
+		PawnStorm:          pawnStorm,
+		OpenFile:           openFilePenalty,
+		SemiOpenFile:       semiOpenFilePenalty,
+
+		Hanging:      Score{hangingMg, hangingEg},
+		CheckPenalty: checkPenalty,
+	}
+	for piece, value := range PieceValues {
+		if piece != board.King {
+			p.PieceValues[piece] = value
+		}
+	}
+	copy(p.KnightMobilityMg[:], knightMobilityMg)
+	copy(p.KnightMobilityEg[:], knightMobilityEg)
+	copy(p.BishopMobilityMg[:], bishopMobilityMg)
+	copy(p.BishopMobilityEg[:], bishopMobilityEg)
+	copy(p.RookMobilityMg[:], rookMobilityMg)
+	copy(p.RookMobilityEg[:], rookMobilityEg)
+	copy(p.QueenMobilityMg[:], queenMobilityMg)
+	copy(p.QueenMobilityEg[:], queenMobilityEg)
+	return p
+}
+
+// params — веса, которыми сейчас пользуется Evaluate. Указатель заменяется целиком,
+// поэтому веса можно менять, пока другие горутины оценивают позиции.
+var params atomic.Pointer[Params]
+
+func init() {
+	p := DefaultParams()
+	params.Store(&p)
+}
+
+// CurrentParams возвращает веса, которыми сейчас пользуется Evaluate
+func CurrentParams() Params {
+	return *params.Load()
+}
+
+// SetParams задаёт веса для последующих оценок
+func SetParams(p Params) {
+	params.Store(&p)
+}
+
+// Weights возвращает указатели на все настраиваемые веса p в порядке полей
+// для перебора при настройке. Пропускаются поля с тегом tune:"-" (делители) и элементы
+// массивов, которые не отбирает tunable.
+func (p *Params) Weights() []*int {
+	var weights []*int
+	walkParams(reflect.ValueOf(p).Elem(), "", "", nil, false, func(_ string, w *int) {
+		weights = append(weights, w)
+	})
+	return weights
+}
+
+// tunable отбирает по индексам настраиваемые элементы массивов Params: элементы, которые
+// оценка никогда не читает, перебирать при настройке бесполезно.
+var tunable = map[string]func(index []int) bool{
+	"PieceValues":        isPiece,
+	"MgPST":              pstSquare,
+	"EgPST":              pstSquare,
+	"Connected":          pawnRank,
+	"PassedMg":           pawnRank,
+	"PassedEg":           pawnRank,
+	"KingAttackerWeight": isAttacker,
+	"SafeCheckWeight":    isAttacker,
+	"PawnStorm":          func(index []int) bool { return index[0] != 0 }, // 0 — пешки соперника нет
+}
+
+// isPiece отбирает фигуры от пешки до ферзя: король в материал не входит
+func isPiece(index []int) bool {
+	return index[0] >= int(board.Pawn) && index[0] <= int(board.Queen)
+}
+
+// pstSquare отбирает клетки таблиц фигура-клетка, на которых может стоять фигура
+func pstSquare(index []int) bool {
+	if board.Piece(index[0]) == board.Pawn {
+		return pawnRank(index[1:])
+	}
+	return index[0] != int(board.Empty)
+}
+
+// pawnRank отбирает горизонтали, на которых может стоять пешка
+func pawnRank(index []int) bool {
+	return index[0] > 0 && index[0] < 7
+}
+
+// isAttacker отбирает фигуры, которые учитываются в атаке на короля
+func isAttacker(index []int) bool {
+	return index[0] >= int(board.Knight) && index[0] <= int(board.Queen)
+}
+
+// walkParams вызывает visit для каждого целого поля v с его именем вида PawnShield[2]
+// или Doubled.Mg. field — поле Params, внутри которого лежит v, index — индексы v в нём.
+// Если не all, обходятся только настраиваемые веса, см. Weights.
+func walkParams(v reflect.Value, name, field string, index []int, all bool, visit func(name string, w *int)) {
+	switch v.Kind() {
+	case reflect.Int:
+		if filter, ok := tunable[field]; ok && !all && !filter(index) {
+			return
+		}
+		visit(name, v.Addr().Interface().(*int))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkParams(v.Index(i), fmt.Sprintf("%s[%d]", name, i), field, append(index[:len(index):len(index)], i), all, visit)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if all || f.Tag.Get("tune") != "-" {
+				walkParams(v.Field(i), fieldName(name, f.Name), f.Name, nil, all, visit)
+			}
+		}
+	}
+}
+
+func fieldName(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,147 @@
+package evaluation
+
+import (
+	"chess-engine/board"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// ParamsFile — файл весов оценки, который движок загружает при запуске, если он есть
+const ParamsFile = "eval_params.json"
+
+// maxWeight — наибольший по модулю вес: даже сумма всех признаков остаётся далеко от оценки мата
+const maxWeight = 5000
+
+// LoadParams читает веса оценки из JSON-файла path в формате cmd/tune. Веса, которых
+// нет в файле, берутся из DefaultParams.
+func LoadParams(path string) (Params, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Params{}, err
+	}
+	p, err := ParseParams(data)
+	if err != nil {
+		return Params{}, fmt.Errorf("%s: %v", path, err)
+	}
+	return p, nil
+}
+
+// ParseParams разбирает веса оценки из JSON поверх DefaultParams. Неизвестные поля,
+// массивы другой длины, нецелые числа и недопустимые значения — ошибка.
+func ParseParams(data []byte) (Params, error) {
+	p := DefaultParams()
+	if err := checkSchema(data, reflect.TypeOf(p), ""); err != nil {
+		return Params{}, err
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Params{}, err
+	}
+	if err := p.Validate(); err != nil {
+		return Params{}, err
+	}
+	return p, nil
+}
+
+// UseParamsFile загружает веса из файла path и задаёт их для последующих оценок;
+// при ошибке веса не меняются
+func UseParamsFile(path string) error {
+	p, err := LoadParams(path)
+	if err != nil {
+		return err
+	}
+	SetParams(p)
+	return nil
+}
+
+// Validate проверяет, что с весами p оценка корректна: фигуры стоят больше нуля,
+// пешки и король не считаются атакующими короля, делители положительны,
+// а веса не выходят за ±maxWeight
+func (p *Params) Validate() error {
+	var err error
+	walkParams(reflect.ValueOf(p).Elem(), "", "", nil, true, func(name string, w *int) {
+		if err == nil && (*w < -maxWeight || *w > maxWeight) {
+			err = fmt.Errorf("%s = %d: вес должен быть от %d до %d", name, *w, -maxWeight, maxWeight)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for piece := board.Pawn; piece < board.King; piece++ {
+		if p.PieceValues[piece] <= 0 {
+			return fmt.Errorf("PieceValues[%d] = %d: стоимость фигуры должна быть положительной", piece, p.PieceValues[piece])
+		}
+	}
+	for _, piece := range []board.Piece{board.Empty, board.Pawn, board.King} {
+		if p.KingAttackerWeight[piece] != 0 {
+			return fmt.Errorf("KingAttackerWeight[%d] = %d: пешки и король не считаются атакующими фигурами", piece, p.KingAttackerWeight[piece])
+		}
+	}
+	divisors := []struct {
+		name  string
+		value int
+	}{
+		{"BlockedPassedDivisor", p.BlockedPassedDivisor},
+		{"KingDangerScale", p.KingDangerScale},
+		{"KingDangerEg", p.KingDangerEg},
+	}
+	for _, d := range divisors {
+		if d.value <= 0 {
+			return fmt.Errorf("%s = %d: делитель должен быть положительным", d.name, d.value)
+		}
+	}
+	if p.MaxKingDanger < 0 {
+		return fmt.Errorf("MaxKingDanger = %d: штраф не может быть отрицательным", p.MaxKingDanger)
+	}
+	return nil
+}
+
+// checkSchema проверяет, что data по форме подходит для значения типа t: у объектов
+// только поля Params с точным именем, у массивов ровно t.Len() элементов, числа целые
+func checkSchema(data json.RawMessage, t reflect.Type, name string) error {
+	switch t.Kind() {
+	case reflect.Int:
+		var n int
+		if err := json.Unmarshal(data, &n); err != nil || string(data) == "null" {
+			return fmt.Errorf("%s: ожидается целое число, а не %s", name, data)
+		}
+	case reflect.Array:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil || items == nil {
+			return fmt.Errorf("%s: ожидается массив из %d элементов", name, t.Len())
+		}
+		if len(items) != t.Len() {
+			return fmt.Errorf("%s: ожидается массив из %d элементов, а не из %d", name, t.Len(), len(items))
+		}
+		for i, item := range items {
+			if err := checkSchema(item, t.Elem(), fmt.Sprintf("%s[%d]", name, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+			if name == "" {
+				return fmt.Errorf("ожидается объект с весами оценки")
+			}
+			return fmt.Errorf("%s: ожидается объект", name)
+		}
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field, ok := t.FieldByName(key)
+			if !ok {
+				return fmt.Errorf("неизвестный параметр %s", fieldName(name, key))
+			}
+			if err := checkSchema(fields[key], field.Type, fieldName(name, key)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
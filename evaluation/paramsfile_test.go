@@ -0,0 +1,72 @@
+package evaluation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseParamsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string // Часть текста ошибки
+	}{
+		{"не объект", `[1, 2]`, "ожидается объект"},
+		{"неизвестное поле", `{"Foo": 1}`, "неизвестный параметр Foo"},
+		{"неизвестное вложенное поле", `{"Doubled": {"Mg": -10, "Xg": 1}}`, "неизвестный параметр Doubled.Xg"},
+		{"поле в другом регистре", `{"checkpenalty": 10}`, "неизвестный параметр checkpenalty"},
+		{"короткий массив", `{"PieceValues": [0, 100, 320, 330, 500, 900]}`, "PieceValues: ожидается массив из 7 элементов, а не из 6"},
+		{"длинный массив", `{"PawnShield": [0, 0, 0, 0, 0, 0, 0, 0, 0]}`, "PawnShield: ожидается массив из 8 элементов, а не из 9"},
+		{"короткая строка таблицы", `{"MgPST": [[], [], [], [], [], [], []]}`, "MgPST[0]: ожидается массив из 8 элементов, а не из 0"},
+		{"дробное число", `{"Doubled": {"Mg": 1.5}}`, "Doubled.Mg: ожидается целое число"},
+		{"строка вместо числа", `{"CheckPenalty": "50"}`, "CheckPenalty: ожидается целое число"},
+		{"null вместо числа", `{"CheckPenalty": null}`, "CheckPenalty: ожидается целое число"},
+		{"нулевой делитель", `{"KingDangerScale": 0}`, "KingDangerScale = 0: делитель должен быть положительным"},
+		{"отрицательный делитель", `{"BlockedPassedDivisor": -2}`, "BlockedPassedDivisor = -2: делитель должен быть положительным"},
+		{"бесплатная фигура", `{"PieceValues": [0, 100, 0, 330, 500, 900, 0]}`, "PieceValues[2] = 0"},
+		{"король атакует", `{"KingAttackerWeight": [0, 0, 20, 20, 40, 80, 10]}`, "KingAttackerWeight[6] = 10"},
+		{"слишком большой вес", `{"OpenFile": 100000}`, "OpenFile = 100000: вес должен быть от -5000 до 5000"},
+		{"некорректный JSON", `{"OpenFile": }`, "ожидается объект"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseParams([]byte(tt.json))
+			if err == nil {
+				t.Fatalf("ParseParams(%s): нет ошибки, want %q", tt.json, tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseParams(%s) = %q, want %q", tt.json, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseParamsDefaults(t *testing.T) {
+	defaults := DefaultParams()
+	if err := defaults.Validate(); err != nil {
+		t.Fatalf("DefaultParams().Validate() = %v", err)
+	}
+
+	p, err := ParseParams([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("ParseParams({}) = %v", err)
+	}
+	if !reflect.DeepEqual(p, defaults) {
+		t.Errorf("ParseParams({}) отличается от DefaultParams()")
+	}
+
+	// Заданные веса заменяют значения по умолчанию, остальные остаются прежними
+	p, err = ParseParams([]byte(`{"CheckPenalty": 70, "Doubled": {"Eg": -25}, "PawnStorm": [0, 0, -30, -15, -8, -3, 0, 0]}`))
+	if err != nil {
+		t.Fatalf("ParseParams = %v", err)
+	}
+	want := defaults
+	want.CheckPenalty = 70
+	want.Doubled.Eg = -25
+	want.PawnStorm[2] = -30
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("ParseParams: CheckPenalty %d, Doubled %+v, PawnStorm %v; want %d, %+v, %v",
+			p.CheckPenalty, p.Doubled, p.PawnStorm, want.CheckPenalty, want.Doubled, want.PawnStorm)
+	}
+}
@@ -0,0 +1,214 @@
+package evaluation
+
+import (
+	"chess-engine/board"
+	"sync"
+)
+
+// Штрафы и бонусы пешечной структуры для миттельшпиля и эндшпиля
+const (
+	doubledMg, doubledEg   = -10, -20 // Пешка, перед которой на той же вертикали стоит своя
+	isolatedMg, isolatedEg = -10, -15 // Пешка без своих пешек на соседних вертикалях
+	backwardMg, backwardEg = -8, -10  // Отставшая пешка, поле перед которой бьёт пешка соперника
+)
+
+// Бонусы по горизонтали от своего края доски: связанной пешке (защищённой своей пешкой
+// или стоящей рядом с ней) и проходной пешке, перед которой нет пешек соперника
+var (
+	connectedBonus = [8]int{0, 3, 5, 8, 12, 20, 30, 0}
+	passedMg       = [8]int{0, 5, 10, 15, 25, 40, 60, 0}
+	passedEg       = [8]int{0, 10, 15, 25, 45, 70, 110, 0}
+)
+
+// Проходная пешка, перед которой стоит фигура, получает только часть бонуса, а в эндшпиле
+// её ценность зависит от того, насколько свой король ближе к полю перед ней, чем чужой
+const (
+	blockedPassedDivisor = 2
+	ownKingDistance      = 2
+	enemyKingDistance    = 5
+)
+
+// pawnTableSize — число записей пешечной хеш-таблицы
+const pawnTableSize = 1 << 14
+
+// pawnEntry — оценка пешечной структуры, которая зависит только от расположения пешек
+type pawnEntry struct {
+	key    uint64
+	valid  bool
+	params *Params   // Веса, с которыми посчитана запись
+	score  [2]Score  // Сдвоенные, изолированные, отставшие и связанные пешки каждого цвета
+	pawns  [2]uint64 // Пешки каждого цвета: бит x*8+y
+	passed [2]uint64 // Проходные пешки каждого цвета
+}
+
+// pawnTable кеширует pawnEntry по board.Board.PawnHash: пешечная структура меняется
+// редко, поэтому её оценка обычно считается один раз на много позиций
+var pawnTable struct {
+	sync.Mutex
+	entries [pawnTableSize]pawnEntry
+}
+
+// evaluatePawns возвращает оценку пешек каждого цвета с весами p. entry — запись
+// пешечной таблицы для b, kings — клетки королей каждого цвета.
+func evaluatePawns(b board.Board, p *Params, entry pawnEntry, kings [2][2]int) [2]Score {
+	score := entry.score
+
+	// Бонусы проходных зависят от фигур и королей, поэтому в таблице хранятся только сами пешки
+	for _, color := range []board.Color{board.White, board.Black} {
+		dir := 1
+		if color == board.Black {
+			dir = -1
+		}
+		own, enemy := kings[color], kings[opposite(color)]
+		for sq := 0; sq < 64; sq++ {
+			if entry.passed[color]&(1<<uint(sq)) == 0 {
+				continue
+			}
+			x, y := sq/8, sq%8
+			rank := relativeRank(color, x)
+			pMg, pEg := p.PassedMg[rank], p.PassedEg[rank]
+			stopX := x + dir
+			if !b.IsEmpty(stopX, y) {
+				pMg /= p.BlockedPassedDivisor
+				pEg /= p.BlockedPassedDivisor
+			}
+			// Чем дальше продвинута пешка, тем важнее, кто из королей успевает к ней
+			if weight := rank - 2; weight > 0 {
+				pEg += (p.EnemyKingDistance*distance(enemy, stopX, y) - p.OwnKingDistance*distance(own, stopX, y)) * weight
+			}
+			score[color].add(pMg, pEg)
+		}
+	}
+	return score
+}
+
+// probePawns возвращает запись пешечной таблицы для позиции b, вычисляя её с весами p
+// при промахе; записи, посчитанные с другими весами, считаются промахом
+func probePawns(b board.Board, p *Params) pawnEntry {
+	key := b.PawnHash()
+	slot := key % pawnTableSize
+	pawnTable.Lock()
+	entry := pawnTable.entries[slot]
+	pawnTable.Unlock()
+	if entry.valid && entry.key == key && entry.params == p {
+		return entry
+	}
+
+	entry = pawnStructure(b, p)
+	entry.key, entry.valid, entry.params = key, true, p
+	pawnTable.Lock()
+	pawnTable.entries[slot] = entry
+	pawnTable.Unlock()
+	return entry
+}
+
+// pawnStructure оценивает сдвоенные, изолированные, отставшие и связанные пешки
+// с весами p и находит проходные
+func pawnStructure(b board.Board, p *Params) pawnEntry {
+	var e pawnEntry
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			if piece, color, _ := b.GetPiece(x, y); piece == board.Pawn {
+				e.pawns[color] |= 1 << uint(x*8+y)
+			}
+		}
+	}
+
+	for _, color := range []board.Color{board.White, board.Black} {
+		dir := 1
+		if color == board.Black {
+			dir = -1
+		}
+		own, enemy := e.pawns[color], e.pawns[opposite(color)]
+		for sq := 0; sq < 64; sq++ {
+			if own&(1<<uint(sq)) == 0 {
+				continue
+			}
+			x, y := sq/8, sq%8
+			mg, eg := 0, 0
+
+			if pawnAhead(own, x, y, dir, 0) {
+				mg += p.Doubled.Mg
+				eg += p.Doubled.Eg
+			}
+			isolated := !pawnOnFile(own, y-1) && !pawnOnFile(own, y+1)
+			if isolated {
+				mg += p.Isolated.Mg
+				eg += p.Isolated.Eg
+			}
+			supported := hasPawn(own, x-dir, y-1) || hasPawn(own, x-dir, y+1)
+			phalanx := hasPawn(own, x, y-1) || hasPawn(own, x, y+1)
+			if supported || phalanx {
+				bonus := p.Connected[relativeRank(color, x)]
+				mg += bonus
+				eg += bonus
+			} else if !isolated && !pawnBehind(own, x, y-1, dir) && !pawnBehind(own, x, y+1, dir) &&
+				(hasPawn(enemy, x+2*dir, y-1) || hasPawn(enemy, x+2*dir, y+1)) {
+				// Соседние пешки ушли вперёд и не могут защитить эту, а продвинуться ей не даёт пешка соперника
+				mg += p.Backward.Mg
+				eg += p.Backward.Eg
+			}
+			if !pawnAhead(enemy, x, y, dir, 0) && !pawnAhead(enemy, x, y, dir, -1) && !pawnAhead(enemy, x, y, dir, 1) {
+				e.passed[color] |= 1 << uint(sq)
+			}
+
+			e.score[color].add(mg, eg)
+		}
+	}
+	return e
+}
+
+// hasPawn проверяет, что в маске pawns есть пешка на клетке (x, y)
+func hasPawn(pawns uint64, x, y int) bool {
+	return x >= 0 && x < 8 && y >= 0 && y < 8 && pawns&(1<<uint(x*8+y)) != 0
+}
+
+// pawnOnFile проверяет, что в маске pawns есть пешка на вертикали y
+func pawnOnFile(pawns uint64, y int) bool {
+	for x := 0; x < 8; x++ {
+		if hasPawn(pawns, x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// pawnAhead проверяет, что на вертикали y+df впереди клетки (x, y) по направлению dir есть пешка из pawns
+func pawnAhead(pawns uint64, x, y, dir, df int) bool {
+	for nx := x + dir; nx >= 0 && nx < 8; nx += dir {
+		if hasPawn(pawns, nx, y+df) {
+			return true
+		}
+	}
+	return false
+}
+
+// pawnBehind проверяет, что на вертикали y на горизонтали x или позади неё есть пешка из pawns
+func pawnBehind(pawns uint64, x, y, dir int) bool {
+	for nx := x; nx >= 0 && nx < 8; nx -= dir {
+		if hasPawn(pawns, nx, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// relativeRank возвращает горизонталь x, отсчитанную от своего края доски стороны color
+func relativeRank(color board.Color, x int) int {
+	if color == board.Black {
+		return 7 - x
+	}
+	return x
+}
+
+// distance возвращает число ходов короля от клетки from до клетки (x, y)
+func distance(from [2]int, x, y int) int {
+	return max(abs(from[0]-x), abs(from[1]-y))
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
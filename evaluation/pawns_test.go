@@ -0,0 +1,79 @@
+package evaluation
+
+import (
+	"chess-engine/board"
+	"testing"
+)
+
+// traceTerm возвращает признак term из разбора оценки позиции fen
+func traceTerm(t *testing.T, fen string, term int) Term {
+	t.Helper()
+	b, _, err := board.ParseFEN(fen)
+	if err != nil {
+		t.Fatalf("ParseFEN(%q): %v", fen, err)
+	}
+	return Trace(b).Terms[term]
+}
+
+func TestPawnStructure(t *testing.T) {
+	tests := []struct {
+		name         string
+		fen          string
+		white, black Score
+	}{
+		// Белая a2 изолирована, чёрные a7 и b7 связаны, проходных нет
+		{"изолированная", "4k3/pp6/8/8/8/8/P7/4K3 w - - 0 1", Score{isolatedMg, isolatedEg}, Score{6, 6}},
+		// Белые a2 и a3 изолированы, a2 ещё и сдвоена
+		{"сдвоенные", "4k3/pp6/8/8/8/P7/P7/4K3 w - - 0 1",
+			Score{doubledMg + 2*isolatedMg, doubledEg + 2*isolatedEg}, Score{6, 6}},
+		// Белая d2 отстала от c4, а поле d3 бьёт чёрная e4; чёрные c7 и e4 изолированы
+		{"отставшая", "4k3/2p5/8/8/2P1p3/8/3P4/4K3 w - - 0 1",
+			Score{backwardMg, backwardEg}, Score{2 * isolatedMg, 2 * isolatedEg}},
+		// Проходная e5 на пятой горизонтали; короли на равном расстоянии с учётом весов
+		{"проходная", "4k3/8/8/4P3/8/8/8/4K3 w - - 0 1",
+			Score{isolatedMg + passedMg[4], isolatedEg + passedEg[4]}, Score{}},
+		// Перед проходной стоит фигура — бонус уменьшается
+		{"блокированная проходная", "4k3/8/4n3/4P3/8/8/8/4K3 w - - 0 1",
+			Score{isolatedMg + passedMg[4]/blockedPassedDivisor, isolatedEg + passedEg[4]/blockedPassedDivisor}, Score{}},
+		// Проходной чёрной пешке ближе свой король: бонус в эндшпиле растёт
+		{"король у проходной", "8/8/8/8/3p4/2k5/8/7K w - - 0 1",
+			Score{}, Score{isolatedMg + passedMg[4], isolatedEg + passedEg[4] + (enemyKingDistance*4-ownKingDistance*1)*2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := traceTerm(t, tt.fen, termPawns)
+			if term.White != tt.white || term.Black != tt.black {
+				t.Errorf("пешки: белые %+v, чёрные %+v; want %+v, %+v", term.White, term.Black, tt.white, tt.black)
+			}
+		})
+	}
+}
+
+func TestPawnHash(t *testing.T) {
+	// Те же пешки с другими фигурами: вторая позиция берёт структуру из пешечной таблицы
+	first, _, _ := board.ParseFEN("4k3/pp6/8/8/2P1p3/8/3P4/4K3 w - - 0 1")
+	second, _, _ := board.ParseFEN("3nk3/pp6/8/8/2P1p3/8/3P4/R3K3 w - - 0 1")
+	if first.PawnHash() != second.PawnHash() {
+		t.Fatalf("PawnHash различается у позиций с одинаковыми пешками")
+	}
+	want := Trace(first).Terms[termPawns]
+	p := params.Load()
+	if entry := probePawns(second, p); !entry.valid || entry.key != second.PawnHash() || entry.params != p {
+		t.Fatalf("нет записи пешечной таблицы после оценки позиции с теми же пешками")
+	}
+	if got := Trace(second).Terms[termPawns]; got != want {
+		t.Errorf("пешки из таблицы: %+v, want %+v", got, want)
+	}
+	if got := Trace(first).Terms[termPawns]; got != want {
+		t.Errorf("повторная оценка: %+v, want %+v", got, want)
+	}
+
+	// Запись, посчитанная с прежними весами, не используется
+	defer SetParams(CurrentParams())
+	changed := CurrentParams()
+	changed.Isolated.Mg -= 100
+	SetParams(changed)
+	if got := Trace(first).Terms[termPawns]; got.Black.Mg != want.Black.Mg-100 {
+		t.Errorf("после смены весов пешки чёрных %+v, want Mg %d", got.Black, want.Black.Mg-100)
+	}
+}
@@ -0,0 +1,171 @@
+package evaluation
+
+import "chess-engine/board"
+
+// MaxPhase — фаза партии, когда на доске все фигуры; 0 — остались только короли и пешки
+const MaxPhase = 24
+
+// Вклад фигур в фазу партии: пешки и короли на неё не влияют
+var phaseWeights = [7]int{
+	board.Knight: 1,
+	board.Bishop: 1,
+	board.Rook:   2,
+	board.Queen:  4,
+}
+
+// Таблицы фигура-клетка для миттельшпиля и эндшпиля с точки зрения белых: строка 0 —
+// первая горизонталь, столбец 0 — вертикаль a. Для чёрных таблицы отражаются по горизонталям.
+var mgPST = [7][8][8]int{
+	board.Pawn: {
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{5, 10, 10, -20, -20, 10, 10, 5},
+		{5, -5, -10, 0, 0, -10, -5, 5},
+		{0, 0, 0, 20, 20, 0, 0, 0},
+		{5, 5, 10, 25, 25, 10, 5, 5},
+		{10, 10, 20, 30, 30, 20, 10, 10},
+		{50, 50, 50, 50, 50, 50, 50, 50},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+	},
+	board.Knight: {
+		{-50, -40, -30, -30, -30, -30, -40, -50},
+		{-40, -20, 0, 5, 5, 0, -20, -40},
+		{-30, 5, 10, 15, 15, 10, 5, -30},
+		{-30, 0, 15, 20, 20, 15, 0, -30},
+		{-30, 5, 15, 20, 20, 15, 5, -30},
+		{-30, 0, 10, 15, 15, 10, 0, -30},
+		{-40, -20, 0, 0, 0, 0, -20, -40},
+		{-50, -40, -30, -30, -30, -30, -40, -50},
+	},
+	board.Bishop: {
+		{-20, -10, -10, -10, -10, -10, -10, -20},
+		{-10, 5, 0, 0, 0, 0, 5, -10},
+		{-10, 10, 10, 10, 10, 10, 10, -10},
+		{-10, 0, 10, 10, 10, 10, 0, -10},
+		{-10, 5, 5, 10, 10, 5, 5, -10},
+		{-10, 0, 5, 10, 10, 5, 0, -10},
+		{-10, 0, 0, 0, 0, 0, 0, -10},
+		{-20, -10, -10, -10, -10, -10, -10, -20},
+	},
+	board.Rook: {
+		{0, 0, 0, 5, 5, 0, 0, 0},
+		{-5, 0, 0, 0, 0, 0, 0, -5},
+		{-5, 0, 0, 0, 0, 0, 0, -5},
+		{-5, 0, 0, 0, 0, 0, 0, -5},
+		{-5, 0, 0, 0, 0, 0, 0, -5},
+		{-5, 0, 0, 0, 0, 0, 0, -5},
+		{5, 10, 10, 10, 10, 10, 10, 5},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+	},
+	board.Queen: {
+		{-20, -10, -10, -5, -5, -10, -10, -20},
+		{-10, 0, 5, 0, 0, 0, 0, -10},
+		{-10, 5, 5, 5, 5, 5, 0, -10},
+		{0, 0, 5, 5, 5, 5, 0, -5},
+		{-5, 0, 5, 5, 5, 5, 0, -5},
+		{-10, 0, 5, 5, 5, 5, 0, -10},
+		{-10, 0, 0, 0, 0, 0, 0, -10},
+		{-20, -10, -10, -5, -5, -10, -10, -20},
+	},
+	board.King: {
+		{20, 30, 10, 0, 0, 10, 30, 20},
+		{20, 20, 0, 0, 0, 0, 20, 20},
+		{-10, -20, -20, -20, -20, -20, -20, -10},
+		{-20, -30, -30, -40, -40, -30, -30, -20},
+		{-30, -40, -40, -50, -50, -40, -40, -30},
+		{-30, -40, -40, -50, -50, -40, -40, -30},
+		{-30, -40, -40, -50, -50, -40, -40, -30},
+		{-30, -40, -40, -50, -50, -40, -40, -30},
+	},
+}
+
+// В эндшпиле пешки ценнее по мере продвижения, а король и фигуры стремятся в центр
+var egPST = [7][8][8]int{
+	board.Pawn: {
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{5, 5, 5, 5, 5, 5, 5, 5},
+		{10, 10, 10, 10, 10, 10, 10, 10},
+		{20, 20, 20, 20, 20, 20, 20, 20},
+		{35, 35, 35, 35, 35, 35, 35, 35},
+		{60, 60, 60, 60, 60, 60, 60, 60},
+		{100, 100, 100, 100, 100, 100, 100, 100},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+	},
+	board.Knight: {
+		{-40, -30, -20, -20, -20, -20, -30, -40},
+		{-30, -10, 0, 0, 0, 0, -10, -30},
+		{-20, 0, 10, 15, 15, 10, 0, -20},
+		{-20, 5, 15, 20, 20, 15, 5, -20},
+		{-20, 5, 15, 20, 20, 15, 5, -20},
+		{-20, 0, 10, 15, 15, 10, 0, -20},
+		{-30, -10, 0, 0, 0, 0, -10, -30},
+		{-40, -30, -20, -20, -20, -20, -30, -40},
+	},
+	board.Bishop: {
+		{-15, -10, -10, -10, -10, -10, -10, -15},
+		{-10, 0, 0, 0, 0, 0, 0, -10},
+		{-10, 0, 5, 5, 5, 5, 0, -10},
+		{-10, 0, 5, 10, 10, 5, 0, -10},
+		{-10, 0, 5, 10, 10, 5, 0, -10},
+		{-10, 0, 5, 5, 5, 5, 0, -10},
+		{-10, 0, 0, 0, 0, 0, 0, -10},
+		{-15, -10, -10, -10, -10, -10, -10, -15},
+	},
+	board.Rook: {
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{10, 10, 10, 10, 10, 10, 10, 10},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+	},
+	board.Queen: {
+		{-20, -10, -10, -10, -10, -10, -10, -20},
+		{-10, 0, 0, 0, 0, 0, 0, -10},
+		{-10, 0, 10, 10, 10, 10, 0, -10},
+		{-10, 0, 10, 20, 20, 10, 0, -10},
+		{-10, 0, 10, 20, 20, 10, 0, -10},
+		{-10, 0, 10, 10, 10, 10, 0, -10},
+		{-10, 0, 0, 0, 0, 0, 0, -10},
+		{-20, -10, -10, -10, -10, -10, -10, -20},
+	},
+	board.King: {
+		{-50, -30, -30, -30, -30, -30, -30, -50},
+		{-30, -30, 0, 0, 0, 0, -30, -30},
+		{-30, -10, 20, 30, 30, 20, -10, -30},
+		{-30, -10, 30, 40, 40, 30, -10, -30},
+		{-30, -10, 30, 40, 40, 30, -10, -30},
+		{-30, -10, 20, 30, 30, 20, -10, -30},
+		{-30, -20, -10, 0, 0, -10, -20, -30},
+		{-50, -40, -30, -20, -20, -30, -40, -50},
+	},
+}
+
+// GamePhase возвращает фазу партии по оставшимся фигурам: от MaxPhase в начале
+// партии до 0, когда остались только короли и пешки
+func GamePhase(b board.Board) int {
+	phase := 0
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			piece, _, _ := b.GetPiece(x, y)
+			phase += phaseWeights[piece]
+		}
+	}
+	// После превращений фигур может оказаться больше, чем в начале партии
+	return min(phase, MaxPhase)
+}
+
+// pieceSquare возвращает бонусы фигуры piece стороны color на клетке (x, y)
+// для миттельшпиля и эндшпиля из таблиц p
+func pieceSquare(p *Params, piece board.Piece, color board.Color, x, y int) (mg, eg int) {
+	if color == board.Black {
+		x = 7 - x
+	}
+	return p.MgPST[piece][x][y], p.EgPST[piece][x][y]
+}
+
+// taper смешивает оценки миттельшпиля mg и эндшпиля eg пропорционально фазе партии
+func taper(mg, eg, phase int) int {
+	return (mg*phase + eg*(MaxPhase-phase)) / MaxPhase
+}
@@ -0,0 +1,148 @@
+package evaluation
+
+import (
+	"chess-engine/board"
+	"chess-engine/move"
+)
+
+var knightOffsets = [8][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+var kingOffsets = [8][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+var diagonalDirections = [4][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+var straightDirections = [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+
+// SEE (static exchange evaluation) возвращает материальный итог размена, который
+// начинает ход m: стороны по очереди бьют на клетке хода самой дешёвой фигурой
+// и могут остановиться, когда продолжать невыгодно. Учитываются дальнобойные фигуры,
+// стоящие за другими (рентген), но не связки. Положительный итог — выигрыш
+// для стороны, которая делает ход.
+func SEE(b board.Board, m move.Move) int {
+	piece, color, _ := b.GetPiece(m.FromX, m.FromY)
+	target, _, _ := b.GetPiece(m.ToX, m.ToY)
+
+	gain := PieceValues[target]
+	if piece == board.Pawn && (m.ToX == 0 || m.ToX == 7) {
+		piece = board.Queen
+		if m.PromoteTo != 0 {
+			piece = m.PromoteTo
+		}
+		gain += PieceValues[piece] - PieceValues[board.Pawn]
+	}
+	b.SetPiece(m.FromX, m.FromY, board.Empty, board.White)
+	return exchange(b, m.ToX, m.ToY, piece, opposite(color), gain)
+}
+
+// Threat возвращает, сколько соперник выигрывает лучшим разменом на клетке (x, y),
+// начав со взятия стоящей там фигуры; 0 — фигура защищена достаточно или её нечем бить.
+// Положительное значение означает, что фигура висит.
+func Threat(b board.Board, x, y int) int {
+	piece, color, _ := b.GetPiece(x, y)
+	if piece == board.Empty || piece == board.King {
+		return 0
+	}
+	attacker := opposite(color)
+	ax, ay, ok := leastValuableAttacker(b, x, y, attacker)
+	if !ok {
+		return 0
+	}
+	return max(0, SEE(b, move.Move{FromX: ax, FromY: ay, ToX: x, ToY: y}))
+}
+
+// exchange доигрывает размен на клетке (x, y), где уже стоит фигура onSquare,
+// а следующей бьёт сторона side. gain — итог для начавшей размен стороны после первого взятия.
+func exchange(b board.Board, x, y int, onSquare board.Piece, side board.Color, gain int) int {
+	// gains[d] — итог размена для стороны, сделавшей d-е взятие, если на нём остановиться
+	gains := []int{gain}
+	for onSquare != board.King {
+		ax, ay, ok := leastValuableAttacker(b, x, y, side)
+		if !ok {
+			break
+		}
+		attacker, _, _ := b.GetPiece(ax, ay)
+		b.SetPiece(ax, ay, board.Empty, board.White)
+		if attacker == board.King {
+			// Король не может бить на клетку, которую ещё бьёт соперник
+			if _, _, defended := leastValuableAttacker(b, x, y, opposite(side)); defended {
+				break
+			}
+		}
+		gains = append(gains, PieceValues[onSquare]-gains[len(gains)-1])
+		if attacker == board.Pawn && (x == 0 || x == 7) {
+			attacker = board.Queen
+			gains[len(gains)-1] += PieceValues[board.Queen] - PieceValues[board.Pawn]
+		}
+		onSquare = attacker
+		side = opposite(side)
+	}
+
+	// Каждая сторона бьёт, только если это не хуже, чем остановиться
+	for d := len(gains) - 1; d > 0; d-- {
+		gains[d-1] = -max(-gains[d-1], gains[d])
+	}
+	return gains[0]
+}
+
+// leastValuableAttacker находит самую дешёвую фигуру стороны color, которая бьёт клетку (x, y)
+func leastValuableAttacker(b board.Board, x, y int, color board.Color) (int, int, bool) {
+	bestX, bestY, bestValue := 0, 0, 0
+	found := false
+	consider := func(ax, ay int, types ...board.Piece) {
+		piece, pieceColor, err := b.GetPiece(ax, ay)
+		if err != nil || piece == board.Empty || pieceColor != color {
+			return
+		}
+		for _, t := range types {
+			if piece == t && (!found || PieceValues[piece] < bestValue) {
+				bestX, bestY, bestValue, found = ax, ay, PieceValues[piece], true
+			}
+		}
+	}
+
+	// Пешка бьёт по диагонали вперёд, поэтому ищем её на горизонталь ближе к своей стороне
+	pawnX := x - 1
+	if color == board.Black {
+		pawnX = x + 1
+	}
+	consider(pawnX, y-1, board.Pawn)
+	consider(pawnX, y+1, board.Pawn)
+	for _, o := range knightOffsets {
+		consider(x+o[0], y+o[1], board.Knight)
+	}
+	for _, d := range diagonalDirections {
+		if ax, ay, ok := firstOnRay(b, x, y, d); ok {
+			consider(ax, ay, board.Bishop, board.Queen)
+		}
+	}
+	for _, d := range straightDirections {
+		if ax, ay, ok := firstOnRay(b, x, y, d); ok {
+			consider(ax, ay, board.Rook, board.Queen)
+		}
+	}
+	for _, o := range kingOffsets {
+		consider(x+o[0], y+o[1], board.King)
+	}
+	return bestX, bestY, found
+}
+
+// firstOnRay возвращает первую фигуру от клетки (x, y) в направлении d
+func firstOnRay(b board.Board, x, y int, d [2]int) (int, int, bool) {
+	for nx, ny := x+d[0], y+d[1]; nx >= 0 && nx < 8 && ny >= 0 && ny < 8; nx, ny = nx+d[0], ny+d[1] {
+		if !b.IsEmpty(nx, ny) {
+			return nx, ny, true
+		}
+	}
+	return 0, 0, false
+}
+
+func opposite(color board.Color) board.Color {
+	if color == board.White {
+		return board.Black
+	}
+	return board.White
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,38 @@
+package evaluation
+
+import (
+	"chess-engine/board"
+	"chess-engine/move"
+	"testing"
+)
+
+func TestSEE(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		move string
+		want int
+	}{
+		{"незащищённая пешка", "4k3/8/8/3p4/8/8/8/3QK3 w - - 0 1", "d1d5", 100},
+		{"пешка защищена только королём", "8/8/4k3/3p4/8/8/8/3QK3 w - - 0 1", "d1d5", -800},
+		{"король не бьёт на защищённую клетку", "8/8/4k3/R2p4/8/8/8/3QK3 w - - 0 1", "d1d5", 100},
+		{"ладья против ладьи", "3r2k1/8/8/3p4/8/8/3R4/4K3 w - - 0 1", "d2d5", -400},
+		{"рентген сдвоенных ладей", "3r2k1/8/8/3p4/8/8/3R4/3RK3 w - - 0 1", "d2d5", 100},
+		{"пешка бьёт защищённого коня", "4k3/8/2p5/3n4/4P3/8/8/4K3 w - - 0 1", "e4d5", 220},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _, err := board.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN(%q): %v", tt.fen, err)
+			}
+			m, err := move.ParseMove(tt.move)
+			if err != nil {
+				t.Fatalf("ParseMove(%q): %v", tt.move, err)
+			}
+			if got := SEE(b, m); got != tt.want {
+				t.Errorf("SEE(%s) = %d, want %d", tt.move, got, tt.want)
+			}
+		})
+	}
+}
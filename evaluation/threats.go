@@ -0,0 +1,25 @@
+package evaluation
+
+import "chess-engine/board"
+
+// Штраф за висящую фигуру в процентах от того, что соперник выигрывает разменом на ней.
+// Оценка не знает, чей ход, и висящую фигуру часто можно увести, поэтому штраф — лишь доля.
+const hangingMg, hangingEg = 15, 10
+
+// evaluateThreats возвращает штрафы каждого цвета за фигуры, которые соперник
+// выигрывает разменом (см. Threat), с весами p
+func evaluateThreats(b board.Board, p *Params) [2]Score {
+	var score [2]Score
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			piece, color, _ := b.GetPiece(x, y)
+			if piece == board.Empty || piece == board.King {
+				continue
+			}
+			if gain := Threat(b, x, y); gain > 0 {
+				score[color].add(-gain*p.Hanging.Mg/100, -gain*p.Hanging.Eg/100)
+			}
+		}
+	}
+	return score
+}
@@ -0,0 +1,32 @@
+package evaluation
+
+import (
+	"chess-engine/board"
+	"testing"
+)
+
+func TestThreats(t *testing.T) {
+	knight, pawn := PieceValues[board.Knight], PieceValues[board.Pawn]
+	tests := []struct {
+		name  string
+		fen   string
+		black Score
+	}{
+		// Ладья бьёт незащищённого коня
+		{"висящая фигура", "4k3/8/8/3n4/8/8/8/3RK3 w - - 0 1",
+			Score{-knight * hangingMg / 100, -knight * hangingEg / 100}},
+		// Пешка защищает коня: взятие ладьёй проигрывает размен
+		{"защищённая фигура", "4k3/8/4p3/3n4/8/8/8/3RK3 w - - 0 1", Score{}},
+		// Пешка выигрывает защищённого коня за себя
+		{"нападение пешкой", "4k3/8/4p3/3n4/2P5/8/8/4K3 w - - 0 1",
+			Score{-(knight - pawn) * hangingMg / 100, -(knight - pawn) * hangingEg / 100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := traceTerm(t, tt.fen, termThreats)
+			if term.Black != tt.black || term.White != (Score{}) {
+				t.Errorf("угрозы: белые %+v, чёрные %+v; want {}, %+v", term.White, term.Black, tt.black)
+			}
+		})
+	}
+}
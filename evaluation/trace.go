@@ -0,0 +1,49 @@
+package evaluation
+
+import (
+	"chess-engine/board"
+	"fmt"
+	"strings"
+)
+
+// Term — вклад признака оценки для белых и чёрных в миттельшпиле и эндшпиле
+type Term struct {
+	Name         string
+	White, Black Score
+}
+
+// Value возвращает вклад признака с точки зрения белых, смешанный по фазе партии phase
+func (t Term) Value(phase int) int {
+	return taper(t.White.Mg-t.Black.Mg, t.White.Eg-t.Black.Eg, phase)
+}
+
+// Breakdown — оценка позиции, разложенная по признакам
+type Breakdown struct {
+	Terms []Term
+	Phase int // Фаза партии, см. GamePhase
+	Total int // Итоговая оценка с точки зрения белых, как у Evaluate
+}
+
+// Trace раскладывает оценку позиции b по признакам, из которых её складывает Evaluate
+func Trace(b board.Board) Breakdown {
+	terms, phase := evaluateTerms(b)
+	res := Breakdown{Phase: phase, Total: Evaluate(b)}
+	for i, term := range terms {
+		res.Terms = append(res.Terms, Term{Name: termNames[i], White: term[board.White], Black: term[board.Black]})
+	}
+	return res
+}
+
+// String выводит разбор таблицей: по каждому признаку оценки белых и чёрных для
+// миттельшпиля и эндшпиля и смешанный по фазе вклад с точки зрения белых
+func (b Breakdown) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-20s %13s %13s %7s\n", "", "Белые", "Чёрные", "")
+	fmt.Fprintf(&sb, "%-20s %6s %6s %6s %6s %7s\n", "Признак", "мит", "энд", "мит", "энд", "Итог")
+	for _, t := range b.Terms {
+		fmt.Fprintf(&sb, "%-20s %6d %6d %6d %6d %+7d\n", t.Name, t.White.Mg, t.White.Eg, t.Black.Mg, t.Black.Eg, t.Value(b.Phase))
+	}
+	fmt.Fprintf(&sb, "%-48s %+7d\n", "Всего", b.Total)
+	fmt.Fprintf(&sb, "Фаза партии %d из %d: миттельшпиль %d%%, эндшпиль %d%%", b.Phase, MaxPhase, b.Phase*100/MaxPhase, 100-b.Phase*100/MaxPhase)
+	return sb.String()
+}
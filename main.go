@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"chess-engine/search"
 	"chess-engine/ui"
 	"fmt"
 	"io"
@@ -60,6 +61,106 @@ func handleConsoleCommands(app *ui.ChessApp) {
 				}
 			}
 
+		case "multipv=":
+			if len(parts) < 2 {
+				log.Println("Ошибка: укажите число вариантов")
+			} else {
+				n, err := strconv.Atoi(parts[1])
+				if err != nil || n <= 0 {
+					log.Println("Ошибка: число вариантов должно быть положительным")
+				} else {
+					app.SetMultiPV(n)
+				}
+			}
+
+		case "draw=":
+			if len(parts) < 2 {
+				log.Println("Ошибка: укажите оценку ничьей")
+			} else {
+				n, err := strconv.Atoi(parts[1])
+				if err != nil || n < -search.MaxDrawValue || n > search.MaxDrawValue {
+					log.Printf("Ошибка: оценка ничьей должна быть целым числом от %d до %d", -search.MaxDrawValue, search.MaxDrawValue)
+				} else {
+					app.SetDrawValue(n)
+				}
+			}
+
+		case "skill=":
+			if len(parts) < 2 {
+				log.Println("Ошибка: укажите уровень игры")
+			} else {
+				level, err := strconv.Atoi(parts[1])
+				if err != nil || level < 1 || level > search.MaxSkillLevel {
+					log.Printf("Ошибка: уровень игры должен быть от 1 до %d", search.MaxSkillLevel)
+				} else {
+					app.SetSkillLevel(level)
+				}
+			}
+
+		case "elo=":
+			if len(parts) < 2 {
+				log.Println("Ошибка: укажите рейтинг")
+			} else {
+				elo, err := strconv.Atoi(parts[1])
+				if err != nil {
+					log.Println("Ошибка: рейтинг должен быть целым числом")
+				} else if elo < search.MinElo || elo > search.MaxElo {
+					log.Printf("Ошибка: рейтинг должен быть от %d до %d", search.MinElo, search.MaxElo)
+				} else {
+					app.SetSkillLevel(search.SkillFromElo(elo))
+				}
+			}
+
+		case "trace=":
+			if len(parts) < 2 {
+				log.Println("Ошибка: укажите файл трассировки или off")
+			} else if parts[1] == "off" {
+				app.SetTrace("", 0)
+			} else {
+				ply := 0
+				if len(parts) > 2 {
+					n, err := strconv.Atoi(parts[2])
+					if err != nil || n <= 0 {
+						log.Println("Ошибка: глубина трассировки должна быть положительной")
+						break
+					}
+					ply = n
+				}
+				app.SetTrace(parts[1], ply)
+			}
+
+		case "seed=":
+			if len(parts) < 2 {
+				log.Println("Ошибка: укажите начальное значение или off")
+			} else if parts[1] == "off" {
+				app.SetSeed(0, false)
+			} else {
+				seed, err := strconv.ParseInt(parts[1], 10, 64)
+				if err != nil {
+					log.Println("Ошибка: начальное значение должно быть целым числом")
+				} else {
+					app.SetSeed(seed, true)
+				}
+			}
+
+		case "params=":
+			if len(parts) < 2 {
+				log.Println("Ошибка: укажите файл весов оценки или default")
+			} else if parts[1] == "default" {
+				app.LoadEvalParams("")
+			} else {
+				app.LoadEvalParams(parts[1])
+			}
+
+		case "reload":
+			app.ReloadEvalParams()
+
+		case "analyze":
+			app.Analyze()
+
+		case "ponder":
+			app.TogglePondering()
+
 		case "reset":
 			app.Reset()
 
@@ -67,7 +168,7 @@ func handleConsoleCommands(app *ui.ChessApp) {
 			app.PrintLastMoveEval()
 
 		case "help":
-			log.Println("pause, help, depth= <value>, reset, eval, exit= <flag>")
+			log.Println("pause, help, depth= <value>, multipv= <value>, draw= <value>, skill= <value>, elo= <value>, trace= <file> [ply] | off, seed= <value> | off, params= <file> | default, reload, analyze, ponder, reset, eval, exit= <flag>")
 
 		case "exit=":
 			if len(parts) < 2 {
@@ -50,10 +50,19 @@ func GenerateMoves(b board.Board, color board.Color) []Move {
 func generateCastlingMoves(b board.Board, x, y int, color board.Color) []Move {
 	var moves []Move
 
-	// Проверяем, может ли король рокироваться
+	// Проверяем, может ли король рокироваться. Рокировка из-под шаха и через битое поле
+	// запрещена; на битое поле назначения король не встанет из-за проверки шаха в GenerateMoves.
 	if x == 0 && y == 4 && color == board.White || x == 7 && y == 4 && color == board.Black {
+		enemy := board.White
+		if color == board.White {
+			enemy = board.Black
+		}
+		if IsSquareAttacked(b, x, y, enemy) {
+			return nil
+		}
+
 		// Короткая рокировка (O-O)
-		if b.IsEmpty(x, y+1) && b.IsEmpty(x, y+2) {
+		if b.IsEmpty(x, y+1) && b.IsEmpty(x, y+2) && !IsSquareAttacked(b, x, y+1, enemy) {
 			rookPiece, rookColor, _ := b.GetPiece(x, y+3)
 			if rookPiece == board.Rook && rookColor == color {
 				moves = append(moves, Move{FromX: x, FromY: y, ToX: x, ToY: y + 2})
@@ -61,7 +70,7 @@ func generateCastlingMoves(b board.Board, x, y int, color board.Color) []Move {
 		}
 
 		// Длинная рокировка (O-O-O)
-		if b.IsEmpty(x, y-1) && b.IsEmpty(x, y-2) && b.IsEmpty(x, y-3) {
+		if b.IsEmpty(x, y-1) && b.IsEmpty(x, y-2) && b.IsEmpty(x, y-3) && !IsSquareAttacked(b, x, y-1, enemy) {
 			rookPiece, rookColor, _ := b.GetPiece(x, y-4)
 			if rookPiece == board.Rook && rookColor == color {
 				moves = append(moves, Move{FromX: x, FromY: y, ToX: x, ToY: y - 2})
@@ -72,6 +81,55 @@ func generateCastlingMoves(b board.Board, x, y int, color board.Color) []Move {
 	return moves
 }
 
+// IsSquareAttacked проверяет, бьёт ли какая-нибудь фигура цвета by клетку (x, y)
+func IsSquareAttacked(b board.Board, x, y int, by board.Color) bool {
+	is := func(nx, ny int, pieces ...board.Piece) bool {
+		piece, color, err := b.GetPiece(nx, ny)
+		if err != nil || piece == board.Empty || color != by {
+			return false
+		}
+		for _, p := range pieces {
+			if piece == p {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Пешка бьёт по диагонали вперёд, поэтому ищем её на горизонталь ближе к её стороне
+	pawnX := x - 1
+	if by == board.Black {
+		pawnX = x + 1
+	}
+	if is(pawnX, y-1, board.Pawn) || is(pawnX, y+1, board.Pawn) {
+		return true
+	}
+	for _, d := range [][2]int{{2, 1}, {2, -1}, {-2, 1}, {-2, -1}, {1, 2}, {1, -2}, {-1, 2}, {-1, -2}} {
+		if is(x+d[0], y+d[1], board.Knight) {
+			return true
+		}
+	}
+	for _, d := range [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}} {
+		if is(x+d[0], y+d[1], board.King) {
+			return true
+		}
+		// Дальнобойные фигуры — первая фигура на луче
+		sliders := []board.Piece{board.Rook, board.Queen}
+		if d[0] != 0 && d[1] != 0 {
+			sliders = []board.Piece{board.Bishop, board.Queen}
+		}
+		for nx, ny := x+d[0], y+d[1]; nx >= 0 && nx < 8 && ny >= 0 && ny < 8; nx, ny = nx+d[0], ny+d[1] {
+			if !b.IsEmpty(nx, ny) {
+				if is(nx, ny, sliders...) {
+					return true
+				}
+				break
+			}
+		}
+	}
+	return false
+}
+
 // generatePawnMoves генерирует ходы для пешки
 func generatePawnMoves(b board.Board, x, y int, color board.Color) []Move {
 	var moves []Move
@@ -83,7 +141,7 @@ func generatePawnMoves(b board.Board, x, y int, color board.Color) []Move {
 
 	// Ход на одну клетку вперед
 	if b.IsEmpty(x+direction, y) {
-		moves = append(moves, Move{FromX: x, FromY: y, ToX: x + direction, ToY: y})
+		moves = appendPawnMove(moves, Move{FromX: x, FromY: y, ToX: x + direction, ToY: y})
 	}
 
 	// Ход на две клетки вперед (только из начальной позиции)
@@ -98,7 +156,7 @@ func generatePawnMoves(b board.Board, x, y int, color board.Color) []Move {
 		if !b.IsEmpty(x+direction, y+dy) {
 			_, targetColor, _ := b.GetPiece(x+direction, y+dy)
 			if targetColor != color {
-				moves = append(moves, Move{FromX: x, FromY: y, ToX: x + direction, ToY: y + dy})
+				moves = appendPawnMove(moves, Move{FromX: x, FromY: y, ToX: x + direction, ToY: y + dy})
 			}
 		}
 	}
@@ -106,6 +164,22 @@ func generatePawnMoves(b board.Board, x, y int, color board.Color) []Move {
 	return moves
 }
 
+// promotionPieces — фигуры, в которые превращается пешка, от сильной к слабой
+var promotionPieces = []board.Piece{board.Queen, board.Rook, board.Bishop, board.Knight}
+
+// appendPawnMove добавляет ход пешки m, а ход на последнюю горизонталь — отдельным
+// ходом для каждой фигуры превращения
+func appendPawnMove(moves []Move, m Move) []Move {
+	if m.ToX != 0 && m.ToX != 7 {
+		return append(moves, m)
+	}
+	for _, piece := range promotionPieces {
+		m.PromoteTo = piece
+		moves = append(moves, m)
+	}
+	return moves
+}
+
 // generateKnightMoves генерирует ходы для коня
 func generateKnightMoves(b board.Board, x, y int, color board.Color) []Move {
 	var moves []Move
@@ -0,0 +1,56 @@
+package move
+
+import (
+	"chess-engine/board"
+	"reflect"
+	"testing"
+)
+
+func TestCastlingMoves(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		want []string
+	}{
+		{"обе рокировки", "4k3/8/8/8/8/8/8/R3K2R w - - 0 1", []string{"e1g1", "e1c1"}},
+		{"шах", "4k3/8/8/8/8/8/2n5/R3K3 w - - 0 1", nil},
+		{"битое поле d1", "3rk3/8/8/8/8/8/8/R3K2R w - - 0 1", []string{"e1g1"}},
+		{"битое поле назначения c1", "2r1k3/8/8/8/8/8/8/R3K2R w - - 0 1", []string{"e1g1"}},
+		{"битое поле b1 не мешает", "1r2k3/8/8/8/8/8/8/R3K2R w - - 0 1", []string{"e1g1", "e1c1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, color, err := board.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN(%q): %v", tt.fen, err)
+			}
+			var got []string
+			for _, m := range GenerateMoves(b, color) {
+				piece, _, _ := b.GetPiece(m.FromX, m.FromY)
+				if piece == board.King && abs(m.FromY-m.ToY) == 2 {
+					got = append(got, m.String())
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("рокировки = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionMoves(t *testing.T) {
+	b, color, err := board.ParseFEN("8/4P3/8/8/8/8/k7/7K w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range GenerateMoves(b, color) {
+		if m.FromX == 6 {
+			got = append(got, m.String())
+		}
+	}
+	want := []string{"e7e8q", "e7e8r", "e7e8b", "e7e8n"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("превращения = %v, want %v", got, want)
+	}
+}
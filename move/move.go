@@ -11,6 +11,52 @@ type Move struct {
 	PromoteTo    board.Piece // Фигура, в которую превращается пешка (0 если нет превращения)
 }
 
+// String возвращает ход в длинной алгебраической нотации, например e2e4 или e7e8q
+func (m Move) String() string {
+	s := string(rune('a'+m.FromY)) + string(rune('1'+m.FromX)) + string(rune('a'+m.ToY)) + string(rune('1'+m.ToX))
+	switch m.PromoteTo {
+	case board.Knight:
+		s += "n"
+	case board.Bishop:
+		s += "b"
+	case board.Rook:
+		s += "r"
+	case board.Queen:
+		s += "q"
+	}
+	return s
+}
+
+// ParseMove разбирает ход в длинной алгебраической нотации, например e2e4 или e7e8q
+func ParseMove(s string) (Move, error) {
+	if len(s) != 4 && len(s) != 5 {
+		return Move{}, errors.New("некорректная запись хода: " + s)
+	}
+	m := Move{
+		FromY: int(s[0] - 'a'), FromX: int(s[1] - '1'),
+		ToY: int(s[2] - 'a'), ToX: int(s[3] - '1'),
+	}
+	if m.FromX < 0 || m.FromX >= 8 || m.FromY < 0 || m.FromY >= 8 ||
+		m.ToX < 0 || m.ToX >= 8 || m.ToY < 0 || m.ToY >= 8 {
+		return Move{}, errors.New("некорректная запись хода: " + s)
+	}
+	if len(s) == 5 {
+		switch s[4] {
+		case 'n':
+			m.PromoteTo = board.Knight
+		case 'b':
+			m.PromoteTo = board.Bishop
+		case 'r':
+			m.PromoteTo = board.Rook
+		case 'q':
+			m.PromoteTo = board.Queen
+		default:
+			return Move{}, errors.New("некорректная фигура превращения: " + s)
+		}
+	}
+	return m, nil
+}
+
 // MakeMove выполняет ход на доске
 func MakeMove(b *board.Board, m Move) error {
 	if m.FromX < 0 || m.FromX >= 8 || m.FromY < 0 || m.FromY >= 8 ||
@@ -0,0 +1,39 @@
+package move
+
+import (
+	"chess-engine/board"
+	"testing"
+)
+
+func TestParseMove(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Move
+	}{
+		{"e2e4", Move{FromX: 1, FromY: 4, ToX: 3, ToY: 4}},
+		{"a1h8", Move{FromX: 0, FromY: 0, ToX: 7, ToY: 7}},
+		{"e7e8q", Move{FromX: 6, FromY: 4, ToX: 7, ToY: 4, PromoteTo: board.Queen}},
+		{"b2a1n", Move{FromX: 1, FromY: 1, ToX: 0, ToY: 0, PromoteTo: board.Knight}},
+	}
+	for _, tt := range tests {
+		got, err := ParseMove(tt.in)
+		if err != nil {
+			t.Errorf("ParseMove(%q) = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMove(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if s := got.String(); s != tt.in {
+			t.Errorf("ParseMove(%q).String() = %q", tt.in, s)
+		}
+	}
+}
+
+func TestParseMoveErrors(t *testing.T) {
+	for _, in := range []string{"", "e2", "e2e", "e2e4qq", "i2e4", "e9e4", "e2e0", "e7e8k", "e7e8x"} {
+		if m, err := ParseMove(in); err == nil {
+			t.Errorf("ParseMove(%q) = %+v, want ошибка", in, m)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package search
+
+import (
+	"chess-engine/board"
+	"chess-engine/move"
+)
+
+// fiftyMoveLimit — число полуходов без взятий и ходов пешек, после которого объявляется ничья
+const fiftyMoveLimit = 100
+
+// SetGameHistory сообщает Searcher ход партии до позиции, в которой будет искать:
+// history — хеши board.Board.Hash предыдущих позиций от первой к последней,
+// rule50 — полуходов без взятий и ходов пешек к текущей позиции. Поиск считает
+// ничьей повтор позиции внутри дерева и трёхкратное повторение с учётом партии.
+func (s *Searcher) SetGameHistory(history []uint64, rule50 int) {
+	s.gameHistory = append([]uint64(nil), history...)
+	s.rule50 = rule50
+}
+
+// isDraw проверяет ничью по правилу пятидесяти ходов или повторением позиции b в узле ply,
+// где ходит сторона color
+func (s *Searcher) isDraw(b board.Board, ply int, color board.Color) bool {
+	node := s.stack[ply]
+	if node.rule50 >= fiftyMoveLimit {
+		// Мат сотым полуходом важнее правила пятидесяти ходов
+		return !move.IsKingInCheck(b, color) || len(move.GenerateMoves(b, color)) > 0
+	}
+
+	// Повтор возможен только среди позиций с той же стороной, которая ходит,
+	// и не раньше последнего взятия или хода пешки
+	inGame := 0
+	for d := 2; d <= node.rule50; d += 2 {
+		if d <= ply {
+			if s.stack[ply-d].hash == node.hash {
+				return true // Повтор внутри дерева: продолжать линию ни одной стороне не выгодно
+			}
+			continue
+		}
+		i := len(s.gameHistory) - (d - ply)
+		if i < 0 {
+			break
+		}
+		if s.gameHistory[i] == node.hash {
+			inGame++
+			if inGame >= 2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// drawScore возвращает оценку ничьей с точки зрения стороны color: Options.DrawValue
+// для стороны, за которую ведётся поиск, и противоположную для соперника
+func (s *Searcher) drawScore(color board.Color) int {
+	if color == s.rootColor {
+		return s.options.DrawValue
+	}
+	return -s.options.DrawValue
+}
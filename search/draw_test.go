@@ -0,0 +1,94 @@
+package search
+
+import (
+	"chess-engine/board"
+	"context"
+	"testing"
+)
+
+func TestIsDrawFiftyMoves(t *testing.T) {
+	tests := []struct {
+		name   string
+		fen    string
+		rule50 int
+		want   bool
+	}{
+		{"99 полуходов", "k7/8/8/8/8/8/8/K6R b - - 0 1", 99, false},
+		{"100 полуходов", "k7/8/8/8/8/8/8/K6R b - - 0 1", 100, true},
+		{"шах сотым полуходом", "k7/8/8/8/8/8/8/K6Q b - - 0 1", 100, true},
+		{"мат сотым полуходом", "k7/1Q6/1K6/8/8/8/8/8 b - - 0 1", 100, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, color, err := board.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := NewSearcher()
+			s.stack[0] = plyState{hash: b.Hash(color), rule50: tt.rule50}
+			if got := s.isDraw(b, 0, color); got != tt.want {
+				t.Errorf("isDraw = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDrawRepetition(t *testing.T) {
+	tests := []struct {
+		name    string
+		hashes  []uint64 // Хеши узлов от корня до проверяемого
+		rule50  int      // Полуходов без взятий и ходов пешек к проверяемому узлу
+		history []uint64
+		want    bool
+	}{
+		{"повтор в дереве", []uint64{7, 1, 2, 3, 7}, 4, nil, true},
+		{"повтор до взятия", []uint64{7, 1, 2, 3, 7}, 3, nil, false},
+		{"нет повтора", []uint64{7, 1, 2, 3, 4}, 4, nil, false},
+		{"третье повторение с партией", []uint64{7}, 8, []uint64{7, 5, 7, 6}, true},
+		{"второе повторение с партией", []uint64{7}, 8, []uint64{1, 5, 7, 6}, false},
+		{"повторения до взятия", []uint64{7}, 2, []uint64{7, 5, 7, 6}, false},
+		{"повторение в партии и в дереве", []uint64{7, 1, 7}, 6, []uint64{7, 5}, true},
+	}
+	b := board.NewBoard()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSearcher()
+			s.SetGameHistory(tt.history, 0)
+			ply := len(tt.hashes) - 1
+			for i, h := range tt.hashes {
+				s.stack[i] = plyState{hash: h, rule50: tt.rule50 - (ply - i)}
+			}
+			if got := s.isDraw(b, ply, board.White); got != tt.want {
+				t.Errorf("isDraw = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDrawScore(t *testing.T) {
+	s := NewSearcher()
+	opts := s.Options()
+	opts.DrawValue = 50
+	s.SetOptions(opts)
+	s.rootColor = board.Black
+	if got := s.drawScore(board.Black); got != 50 {
+		t.Errorf("drawScore(Black) = %d, want 50", got)
+	}
+	if got := s.drawScore(board.White); got != -50 {
+		t.Errorf("drawScore(White) = %d, want -50", got)
+	}
+}
+
+// Мат сотым полуходом без взятий остаётся матом, а не ничьей
+func TestFiftyMoveMate(t *testing.T) {
+	b, color, err := board.ParseFEN("k7/8/1K6/8/8/8/8/6Q1 w - - 99 80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewSearcher()
+	s.SetGameHistory(nil, board.HalfmoveClock("k7/8/1K6/8/8/8/8/6Q1 w - - 99 80"))
+	res, _ := s.FindBestMove(context.Background(), b, color, SearchLimits{Depth: 2, Infinite: true})
+	if res.MateIn != 1 || len(res.BestMoves) == 0 || res.BestMoves[0].String() != "g1g8" {
+		t.Errorf("FindBestMove: ход %v, мат в %d; want g1g8, мат в 1", res.BestMoves, res.MateIn)
+	}
+}
@@ -0,0 +1,96 @@
+package search
+
+import (
+	"chess-engine/board"
+	"chess-engine/move"
+	"context"
+)
+
+// maxPly — наибольшая длина пути от корня с учётом продлений
+const maxPly = 2 * MaxDepth
+
+// Параметры продлений поиска
+const (
+	singularMinDepth  = 4  // С этой глубины ход из таблицы проверяется на единственность
+	singularTTDepth   = 3  // Насколько запись таблицы может быть мельче текущего узла
+	singularMargin    = 20 // Запас на каждый уровень глубины, с которым остальные ходы должны уступать ходу из таблицы
+	noCaptureSquare   = -1
+	defaultExtensions = 4 // Продлений на одном пути по умолчанию
+)
+
+// plyState — сведения о пути от корня до узла на данном полуходе
+type plyState struct {
+	extensions    int      // Сколько продлений уже сделано на пути
+	captureSquare int      // Клетка взятия ходом, который привёл в узел, или noCaptureSquare
+	hash          uint64   // Хеш позиции в узле; заполняет сам узел
+	rule50        int      // Полуходов без взятий и ходов пешек к этому узлу
+	lastMove      pathMove // Ход, который привёл в узел; piece < 0 для корня и нулевого хода
+}
+
+// enterPly записывает сведения о пути к узлу на полуходе ply+1 после хода m из узла ply
+func (s *Searcher) enterPly(b board.Board, m move.Move, ply int, extension int) {
+	piece, color, _ := b.GetPiece(m.FromX, m.FromY)
+	next := plyState{
+		extensions:    s.stack[ply].extensions + extension,
+		captureSquare: noCaptureSquare,
+		rule50:        s.stack[ply].rule50 + 1,
+		lastMove:      pathMove{piece: pieceIndex(piece, color), to: m.ToX*8 + m.ToY},
+	}
+	if !b.IsEmpty(m.ToX, m.ToY) {
+		next.captureSquare = m.ToX*8 + m.ToY
+		next.rule50 = 0
+	}
+	if piece == board.Pawn {
+		next.rule50 = 0
+	}
+	s.stack[ply+1] = next
+}
+
+// extension возвращает, на сколько полуходов продлить поиск после хода m из позиции b:
+// за шах, единственный ход из таблицы, взятие в ответ на взятие и продвижение пешки
+// на предпоследнюю горизонталь. Продлений на одном пути не больше Options.MaxExtensions.
+func (s *Searcher) extension(b board.Board, m move.Move, ply int, givesCheck bool, singular bool) int {
+	if s.stack[ply].extensions >= s.options.MaxExtensions || ply+1 >= maxPly-1 {
+		return 0
+	}
+	if s.options.CheckExtension && givesCheck {
+		return 1
+	}
+	if s.options.SingularExtension && singular {
+		return 1
+	}
+	target, _, _ := b.GetPiece(m.ToX, m.ToY)
+	if s.options.RecaptureExtension && target != board.Empty && s.stack[ply].captureSquare == m.ToX*8+m.ToY {
+		return 1
+	}
+	piece, color, _ := b.GetPiece(m.FromX, m.FromY)
+	if s.options.PassedPawnExtension && piece == board.Pawn &&
+		((color == board.White && m.ToX == 6) || (color == board.Black && m.ToX == 1)) {
+		return 1
+	}
+	return 0
+}
+
+// isSingular проверяет, что ход из таблицы ttMove с оценкой ttScore заметно лучше
+// всех остальных: каждый из них при поиске на половинной глубине не достигает
+// ttScore с запасом singularMargin на уровень глубины
+func (s *Searcher) isSingular(ctx context.Context, b board.Board, moves []move.Move, ttMove move.Move, ttScore int, depth int, ply int, color board.Color, stats *SearchStats) bool {
+	singularBeta := ttScore - singularMargin*depth
+	for _, m := range moves {
+		if m == ttMove {
+			continue
+		}
+		newBoard := b.Copy()
+		if err := move.MakeMove(&newBoard, m); err != nil {
+			continue
+		}
+		stats.NodesEvaluated++
+		s.enterPly(b, m, ply, 0)
+		s.traceMove(ply, m)
+		score := -s.Negamax(ctx, newBoard, (depth-1)/2, ply+1, -singularBeta, -singularBeta+1, opponent(color), true, stats).Score
+		if score >= singularBeta || ctx.Err() != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"chess-engine/board"
+	"chess-engine/move"
+	"context"
+	"errors"
+	"time"
+)
+
+// MaxDepth — наибольшая глубина итеративного углубления, если SearchLimits.Depth не задана
+const MaxDepth = 64
+
+// Распределение времени по часам: без MovesToGo считаем, что до конца партии
+// осталось defaultMovesToGo ходов, и оставляем moveOverhead на задержки передачи хода
+const (
+	defaultMovesToGo = 30
+	moveOverhead     = 50 * time.Millisecond
+	minMoveTime      = 10 * time.Millisecond
+)
+
+// SearchLimits — ограничения поиска. Нулевые поля поиск не ограничивают. Если не задано
+// ни MoveTime, ни время на часах стороны, которая ходит, и Infinite == false,
+// поиск длится не дольше DefaultTimeLimit.
+type SearchLimits struct {
+	Depth        int           // Наибольшая глубина итеративного углубления; 0 — MaxDepth
+	Nodes        int           // Наибольшее число узлов
+	MoveTime     time.Duration // Точное время на ход
+	WTime, BTime time.Duration // Время на часах белых и чёрных
+	WInc, BInc   time.Duration // Добавка времени за ход
+	MovesToGo    int           // Ходов до следующего контроля времени; 0 — до конца партии
+	Mate         int           // Искать мат не более чем в N ходов и остановиться, найдя его
+	SearchMoves  []move.Move   // Рассматривать в корне только эти ходы
+	Infinite     bool          // Не ограничивать время: искать до отмены ctx или до Depth
+	MultiPV      int           // Сколько лучших вариантов вернуть в Lines; 0 — один
+	Skill        int           // Уровень игры от 1 до MaxSkillLevel; 0 и MaxSkillLevel — полная сила
+	ponder       *ponderClock  // Поиск на время соперника: время на ход отсчитывается с Ponder.Hit
+}
+
+// maxDepth возвращает наибольшую глубину итеративного углубления
+func (l SearchLimits) maxDepth() int {
+	depth := l.Depth
+	if l.Mate > 0 && (depth == 0 || depth > 2*l.Mate) {
+		// Мат в N ходов — это 2N-1 полуходов, и ещё один нужен, чтобы увидеть, что у соперника нет ходов
+		depth = 2 * l.Mate
+	}
+	if depth <= 0 || depth > MaxDepth {
+		depth = MaxDepth
+	}
+	return depth
+}
+
+// multiPV возвращает число вариантов анализа
+func (l SearchLimits) multiPV() int {
+	if l.MultiPV < 1 {
+		return 1
+	}
+	return l.MultiPV
+}
+
+// timeBudget возвращает время на ход для стороны color: по истечении hard поиск
+// прерывается, а после soft новая итерация не начинается. Нулевой hard — без ограничения.
+func (l SearchLimits) timeBudget(color board.Color) (hard, soft time.Duration) {
+	if l.Infinite {
+		return 0, 0
+	}
+	if l.MoveTime > 0 {
+		return l.MoveTime, l.MoveTime
+	}
+
+	left, inc := l.WTime, l.WInc
+	if color == board.Black {
+		left, inc = l.BTime, l.BInc
+	}
+	if left <= 0 {
+		return DefaultTimeLimit, DefaultTimeLimit
+	}
+
+	movesToGo := l.MovesToGo
+	if movesToGo <= 0 {
+		movesToGo = defaultMovesToGo
+	}
+	reserve := left - moveOverhead
+	if reserve < minMoveTime {
+		reserve = minMoveTime
+	}
+	// Итерация обычно длится дольше всех предыдущих вместе, поэтому новую начинаем,
+	// только если прошло меньше половины расчётного времени
+	soft = left/time.Duration(movesToGo) + inc*3/4
+	if soft > reserve {
+		soft = reserve
+	}
+	hard = soft * 3
+	if hard > reserve {
+		hard = reserve
+	}
+	return hard, soft / 2
+}
+
+// mateFound сообщает, что найден мат не дальше, чем просили в Mate
+func (l SearchLimits) mateFound(score int) bool {
+	n := MateIn(score)
+	return l.Mate > 0 && n > 0 && n <= l.Mate
+}
+
+// errNodeLimit — причина остановки поиска по исчерпании бюджета узлов
+var errNodeLimit = errors.New("исчерпан бюджет узлов")
+
+// nodeLimitContext прерывает поиск, когда счётчик узлов достигает limit.
+// Поиск проверяет только ctx.Err(), поэтому Done() остаётся от родительского контекста.
+type nodeLimitContext struct {
+	context.Context
+	stats *SearchStats
+	limit int
+}
+
+func (c nodeLimitContext) Err() error {
+	if c.stats.NodesEvaluated >= c.limit {
+		return errNodeLimit
+	}
+	return c.Context.Err()
+}
+
+// restrictMoves оставляет из moves только ходы из allowed; пустой allowed ничего не ограничивает.
+// Превращение без указанной фигуры считается превращением в ферзя, как в move.MakeMove.
+func restrictMoves(moves []move.Move, allowed []move.Move) []move.Move {
+	if len(allowed) == 0 {
+		return moves
+	}
+	var result []move.Move
+	for _, m := range moves {
+		for _, a := range allowed {
+			if a.PromoteTo == 0 && m.PromoteTo == board.Queen {
+				a.PromoteTo = board.Queen
+			}
+			if m == a {
+				result = append(result, m)
+				break
+			}
+		}
+	}
+	return result
+}
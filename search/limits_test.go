@@ -0,0 +1,125 @@
+package search
+
+import (
+	"chess-engine/board"
+	"chess-engine/move"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestRestrictMoves(t *testing.T) {
+	b, color, err := board.ParseFEN("4k3/1P6/8/8/8/8/8/4K2R w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves := move.GenerateMoves(b, color)
+	tests := []struct {
+		name    string
+		allowed []string
+		want    []string
+	}{
+		{"без ограничения", nil, nil},
+		{"обычные ходы", []string{"h1h8", "e1d1"}, []string{"e1d1", "h1h8"}},
+		{"недопустимый ход", []string{"h1a8", "e1d1"}, []string{"e1d1"}},
+		{"превращение без фигуры — ферзь", []string{"b7b8"}, []string{"b7b8q"}},
+		{"превращение в коня", []string{"b7b8n"}, []string{"b7b8n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var allowed []move.Move
+			for _, s := range tt.allowed {
+				m, err := move.ParseMove(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				allowed = append(allowed, m)
+			}
+			got := restrictMoves(moves, allowed)
+			if tt.want == nil {
+				if len(got) != len(moves) {
+					t.Errorf("restrictMoves оставил %d ходов из %d", len(got), len(moves))
+				}
+				return
+			}
+			names := make([]string, len(got))
+			for i, m := range got {
+				names[i] = m.String()
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("restrictMoves = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeBudget(t *testing.T) {
+	tests := []struct {
+		name       string
+		limits     SearchLimits
+		color      board.Color
+		hard, soft time.Duration
+	}{
+		{"без ограничений", SearchLimits{Infinite: true, WTime: time.Minute}, board.White, 0, 0},
+		{"время на ход", SearchLimits{MoveTime: 2 * time.Second}, board.White, 2 * time.Second, 2 * time.Second},
+		{"нет часов", SearchLimits{}, board.White, DefaultTimeLimit, DefaultTimeLimit},
+		{"нет часов у своей стороны", SearchLimits{WTime: time.Minute}, board.Black, DefaultTimeLimit, DefaultTimeLimit},
+		{"часы", SearchLimits{WTime: 60 * time.Second}, board.White, 6 * time.Second, time.Second},
+		{"часы чёрных с добавкой", SearchLimits{WTime: time.Second, BTime: 30 * time.Second, BInc: 2 * time.Second},
+			board.Black, 7500 * time.Millisecond, 1250 * time.Millisecond},
+		{"ходов до контроля", SearchLimits{WTime: 10 * time.Second, MovesToGo: 5}, board.White, 6 * time.Second, time.Second},
+		{"мало времени", SearchLimits{WTime: 100 * time.Millisecond, MovesToGo: 1}, board.White, 50 * time.Millisecond, 25 * time.Millisecond},
+		{"время почти вышло", SearchLimits{WTime: 20 * time.Millisecond, MovesToGo: 1}, board.White, minMoveTime, minMoveTime / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hard, soft := tt.limits.timeBudget(tt.color)
+			if hard != tt.hard || soft != tt.soft {
+				t.Errorf("timeBudget = %v, %v; want %v, %v", hard, soft, tt.hard, tt.soft)
+			}
+		})
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	tests := []struct {
+		limits SearchLimits
+		want   int
+	}{
+		{SearchLimits{}, MaxDepth},
+		{SearchLimits{Depth: 5}, 5},
+		{SearchLimits{Depth: 1000}, MaxDepth},
+		{SearchLimits{Mate: 3}, 6},
+		{SearchLimits{Mate: 3, Depth: 4}, 4},
+		{SearchLimits{Mate: 3, Depth: 10}, 6},
+	}
+	for _, tt := range tests {
+		if got := tt.limits.maxDepth(); got != tt.want {
+			t.Errorf("%+v.maxDepth() = %d, want %d", tt.limits, got, tt.want)
+		}
+	}
+}
+
+func TestMateFound(t *testing.T) {
+	limits := SearchLimits{Mate: 2}
+	tests := []struct {
+		score int
+		want  bool
+	}{
+		{MateScore - 1, true},
+		{MateScore - 3, true},
+		{MateScore - 5, false},
+		{-MateScore + 2, false},
+		{500, false},
+	}
+	for _, tt := range tests {
+		if got := limits.mateFound(tt.score); got != tt.want {
+			t.Errorf("mateFound(%d) = %v, want %v", tt.score, got, tt.want)
+		}
+	}
+	if (SearchLimits{}).mateFound(MateScore - 1) {
+		t.Errorf("mateFound без Mate = true, want false")
+	}
+}
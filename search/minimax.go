@@ -4,48 +4,110 @@ import (
 	"chess-engine/board"
 	"chess-engine/evaluation"
 	"chess-engine/move"
+	"context"
 	"encoding/json"
 	"fmt"
-	"math"
-	"math/rand"
+	"log"
 	"os"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
-type transpositionTableStruct struct {
-	sync.Mutex
-	data map[string]SearchResult
+// Типы границ оценки, сохранённой в транспозиционной таблице
+const (
+	boundExact = iota
+	boundLower
+	boundUpper
+)
+
+// MateScore — оценка мата; мат через ply полуходов от корня оценивается как MateScore-ply,
+// поэтому из двух матов поиск выбирает более быстрый
+const MateScore = 1000000
+
+// mateBound — оценки по модулю больше этой считаются матовыми
+const mateBound = MateScore - 1000
+
+// DefaultTimeLimit — наибольшее время поиска, если в SearchLimits время не задано
+const DefaultTimeLimit = 10 * time.Second
+
+// ttCapacity — наибольшее число позиций в транспозиционной таблице
+const ttCapacity = 1 << 20
+
+// infinity больше любой оценки позиции, включая мат
+const infinity = 1 << 30
+
+// Параметры выборочного поиска
+const (
+	reverseFutilityMargin = 120  // Запас на каждый уровень глубины для обратного отсечения
+	nullVerifyDepth       = 6    // С этой глубины отсечение нулевым ходом перепроверяется
+	lmrHistoryThreshold   = 4000 // Ходы с большей историей сокращаются меньше
+)
+
+// Параметры окон стремления (aspiration windows)
+const (
+	aspirationMinDepth  = 3    // На меньшей глубине поиск идёт с полным окном
+	aspirationWindow    = 50   // Начальная полуширина окна
+	aspirationMaxWindow = 1000 // При большей ширине окно раскрывается полностью
+)
+
+// futilityMargins — запас оценки по глубине, при котором тихие ходы уже не поднимут alpha
+var futilityMargins = [...]int{0, 200, 350, 500}
+
+// lateMoveCounts — сколько тихих ходов просматривается на данной глубине до отсечения остальных
+var lateMoveCounts = [...]int{0, 5, 8, 13}
+
+type ttEntry struct {
+	Move  move.Move `json:"move"`
+	Score int       `json:"score"`
+	Depth int       `json:"depth"`
+	Flag  int       `json:"flag"`
 }
 
-var transpositionTable = transpositionTableStruct{
-	data: make(map[string]SearchResult),
+type transpositionTable struct {
+	sync.Mutex
+	data map[uint64]ttEntry // По хешу board.Board.Hash с учётом очерёдности хода
 }
-var killerMoves [32][2]move.Move
-var history [12][64]int
 
 type SearchResult struct {
 	BestMoves []move.Move `json:"best_moves"`
 	Score     int         `json:"score"`
+	PV        []move.Move `json:"pv"` // Главный вариант, начиная с лучшего хода
+	Lines     []Line      `json:"lines,omitempty"`
+	MateIn    int         `json:"mate_in,omitempty"` // Мат в N ходов (N < 0 — сторона получает мат), 0 — мата нет
+
+	bestPVs [][]move.Move // Варианты ходов из BestMoves в том же порядке; заполняет searchRoot
+}
+
+// Line — один из лучших ходов в режиме MultiPV с его оценкой и вариантом
+type Line struct {
+	Score int         `json:"score"`
+	PV    []move.Move `json:"pv"`
 }
 
 type SearchStats struct {
-	NodesEvaluated int
-	SearchTime     time.Duration
+	NodesEvaluated      int
+	SearchTime          time.Duration
+	Depth               int  // Последняя полностью завершённая итерация
+	SelDepth            int  // Наибольшая достигнутая глубина в полуходах, включая форсированные варианты
+	Stopped             bool // Поиск прерван отменой контекста или по времени
+	AspirationFailLows  int  // Перепоиски после выхода оценки ниже окна
+	AspirationFailHighs int  // Перепоиски после выхода оценки выше окна
 }
 
-func LoadData() {
+// LoadData загружает транспозиционную таблицу и killer moves, сохранённые SaveData
+func (s *Searcher) LoadData() {
 	if data, err := os.ReadFile("transpositions.json"); err == nil {
-		if err := json.Unmarshal(data, &transpositionTable.data); err != nil {
+		if err := json.Unmarshal(data, &s.tt.data); err != nil {
 			fmt.Printf("Ошибка загрузки транспозиционной таблицы: %v\n", err)
 		} else {
-			fmt.Printf("Загружено %d позиций из транспозиционной таблицы\n", len(transpositionTable.data))
+			fmt.Printf("Загружено %d позиций из транспозиционной таблицы\n", len(s.tt.data))
 		}
 	}
 
 	if data, err := os.ReadFile("killers.json"); err == nil {
-		if err := json.Unmarshal(data, &killerMoves); err != nil {
+		if err := json.Unmarshal(data, &s.killerMoves); err != nil {
 			fmt.Printf("Ошибка загрузки killer moves: %v\n", err)
 		} else {
 			fmt.Println("Загружены killer moves")
@@ -53,18 +115,19 @@ func LoadData() {
 	}
 }
 
-func SaveData() {
-	transpositionTable.Lock()
-	defer transpositionTable.Unlock()
-	if data, err := json.MarshalIndent(transpositionTable.data, "", "  "); err == nil {
+// SaveData сохраняет транспозиционную таблицу и killer moves в файлы рядом с программой
+func (s *Searcher) SaveData() {
+	s.tt.Lock()
+	defer s.tt.Unlock()
+	if data, err := json.MarshalIndent(s.tt.data, "", "  "); err == nil {
 		if err := os.WriteFile("transpositions.json", data, 0644); err != nil {
 			fmt.Printf("Ошибка сохранения транспозиционной таблицы: %v\n", err)
 		} else {
-			fmt.Printf("Сохранено %d позиций в транспозиционную таблицу\n", len(transpositionTable.data))
+			fmt.Printf("Сохранено %d позиций в транспозиционную таблицу\n", len(s.tt.data))
 		}
 	}
 
-	if data, err := json.MarshalIndent(killerMoves, "", "  "); err == nil {
+	if data, err := json.MarshalIndent(s.killerMoves, "", "  "); err == nil {
 		if err := os.WriteFile("killers.json", data, 0644); err != nil {
 			fmt.Printf("Ошибка сохранения killer moves: %v\n", err)
 		} else {
@@ -73,192 +136,525 @@ func SaveData() {
 	}
 }
 
-func Minimax(b board.Board, depth int, alpha int, beta int, maximizingPlayer bool, deadline time.Time, stats *SearchStats) SearchResult {
-	if time.Now().After(deadline) {
+// storeTT сохраняет запись в транспозиционной таблице. Переполненная таблица очищается целиком.
+func (s *Searcher) storeTT(hash uint64, entry ttEntry) {
+	s.tt.Lock()
+	defer s.tt.Unlock()
+	if len(s.tt.data) >= ttCapacity {
+		s.tt.data = make(map[uint64]ttEntry)
+	}
+	s.tt.data[hash] = entry
+}
+
+// hashfull возвращает заполненность транспозиционной таблицы в промилле
+func (s *Searcher) hashfull() int {
+	s.tt.Lock()
+	defer s.tt.Unlock()
+	return min(1000, len(s.tt.data)*1000/ttCapacity)
+}
+
+// Clear очищает транспозиционную таблицу, killer moves и историю ходов, например перед новой партией
+func (s *Searcher) Clear() {
+	s.tt.Lock()
+	s.tt.data = make(map[uint64]ttEntry)
+	s.tt.Unlock()
+	s.killerMoves = [maxPly][2]move.Move{}
+	s.history = [12][64]int{}
+	s.counterMoves = [12][64]move.Move{}
+	s.continuation = [12][64][12][64]int{}
+}
+
+// Negamax выполняет поиск с главным вариантом (PVS) в форме негамакса.
+// Оценка возвращается с точки зрения стороны color: первый ход ищется с полным окном,
+// остальные — с нулевым окном и перепроверяются полным окном только при выходе за alpha.
+// Вне главного варианта применяются отсечения из Options. nullAllowed запрещает
+// два нулевых хода подряд. Если задан Options.TraceFile, узел записывается в трассировку.
+func (s *Searcher) Negamax(ctx context.Context, b board.Board, depth int, ply int, alpha int, beta int, color board.Color, nullAllowed bool, stats *SearchStats) SearchResult {
+	if !s.tracing(ply) {
+		return s.negamax(ctx, b, depth, ply, alpha, beta, color, nullAllowed, stats)
+	}
+	seq, saved := s.trace.enter(ply)
+	res := s.negamax(ctx, b, depth, ply, alpha, beta, color, nullAllowed, stats)
+	s.trace.exit(TraceNode{Seq: seq, Ply: ply, Depth: depth, Alpha: alpha, Beta: beta, Score: res.Score}, saved)
+	return res
+}
+
+// negamax — поиск в узле без трассировки, см. Negamax
+func (s *Searcher) negamax(ctx context.Context, b board.Board, depth int, ply int, alpha int, beta int, color board.Color, nullAllowed bool, stats *SearchStats) SearchResult {
+	stats.SelDepth = max(stats.SelDepth, ply)
+	if ctx.Err() != nil || ply >= maxPly-1 {
 		stats.NodesEvaluated++
-		return SearchResult{Score: evaluation.Evaluate(b)}
+		if ctx.Err() != nil {
+			s.traceCutoff(ply, cutoffStopped)
+		}
+		return SearchResult{Score: evaluate(b, color)}
+	}
+	s.stack[ply].hash = b.Hash(color)
+	if s.isDraw(b, ply, color) {
+		stats.NodesEvaluated++
+		s.traceCutoff(ply, cutoffDraw)
+		return SearchResult{Score: s.drawScore(color)}
+	}
+
+	alphaOrig := alpha
+	pvNode := beta-alpha > 1
+	hash := s.stack[ply].hash
+	var ttMove move.Move
+	s.tt.Lock()
+	entry, ok := s.tt.data[hash]
+	s.tt.Unlock()
+	if ok {
+		ttMove = entry.Move
+		s.traceTTHit(ply)
+		// В узлах главного варианта таблица не обрывает поиск, чтобы вариант был полным
+		if entry.Depth >= depth && !pvNode {
+			score := scoreFromTT(entry.Score, ply)
+			switch entry.Flag {
+			case boundExact:
+				s.traceCutoff(ply, cutoffTT)
+				return SearchResult{BestMoves: []move.Move{entry.Move}, Score: score}
+			case boundLower:
+				alpha = max(alpha, score)
+			case boundUpper:
+				beta = min(beta, score)
+			}
+			if alpha >= beta {
+				s.traceCutoff(ply, cutoffTT)
+				return SearchResult{BestMoves: []move.Move{entry.Move}, Score: score}
+			}
+		}
 	}
 
-	hash := boardToString(b)
-	transpositionTable.Lock()
-	if result, ok := transpositionTable.data[hash]; ok && depth <= result.Score {
-		transpositionTable.Unlock()
-		return result
+	if depth <= 0 {
+		return SearchResult{Score: s.QuiescenceSearch(ctx, b, ply, alpha, beta, color, 4, stats)}
 	}
-	transpositionTable.Unlock()
 
-	if depth == 0 {
-		return SearchResult{Score: QuiescenceSearch(b, alpha, beta, maximizingPlayer, 4, deadline, stats)}
+	inCheck := move.IsKingInCheck(b, color)
+	staticEval := 0
+	if !inCheck && !pvNode {
+		staticEval = evaluate(b, color)
 	}
 
-	color := board.Black
-	if maximizingPlayer {
-		color = board.White
+	// Обратное отсечение: позиция настолько хороша, что даже с запасом превышает beta
+	if s.options.ReverseFutility && !inCheck && !pvNode && depth <= 3 && staticEval-reverseFutilityMargin*depth >= beta {
+		s.traceCutoff(ply, cutoffReverseFutility)
+		return SearchResult{Score: staticEval - reverseFutilityMargin*depth}
+	}
+
+	// Нулевой ход: если даже пропуск хода не опускает оценку ниже beta, узел отсекается.
+	// В позициях только с пешками возможен цугцванг, поэтому там нулевой ход не делается,
+	// а на большой глубине результат перепроверяется обычным поиском.
+	if s.options.NullMove && nullAllowed && !inCheck && !pvNode && depth >= 3 && staticEval >= beta && hasNonPawnMaterial(b, color) {
+		r := 2
+		if depth > 6 {
+			r = 3
+		}
+		// Нулевой ход необратим: повтор через него не считается
+		s.stack[ply+1] = plyState{extensions: s.stack[ply].extensions, captureSquare: noCaptureSquare, lastMove: pathMove{piece: -1}}
+		s.traceMove(ply, move.Move{})
+		score := -s.Negamax(ctx, b, depth-1-r, ply+1, -beta, -beta+1, opponent(color), false, stats).Score
+		if score >= beta {
+			if depth < nullVerifyDepth {
+				s.traceCutoff(ply, cutoffNullMove)
+				return SearchResult{Score: beta}
+			}
+			if s.Negamax(ctx, b, depth-1-r, ply, beta-1, beta, color, false, stats).Score >= beta {
+				s.traceCutoff(ply, cutoffNullMove)
+				return SearchResult{Score: beta}
+			}
+		}
 	}
 
 	moves := move.GenerateMoves(b, color)
 	if len(moves) == 0 {
-		if maximizingPlayer && move.IsKingInCheck(b, board.White) {
-			return SearchResult{Score: -1000000}
-		} else if !maximizingPlayer && move.IsKingInCheck(b, board.Black) {
-			return SearchResult{Score: 1000000}
+		if inCheck {
+			s.traceCutoff(ply, cutoffMate)
+			return SearchResult{Score: -MateScore + ply}
 		}
-		fmt.Println("Пат или нет ходов для", color)
 		stats.NodesEvaluated++
-		return SearchResult{Score: evaluation.Evaluate(b)}
+		s.traceCutoff(ply, cutoffStalemate)
+		return SearchResult{Score: s.drawScore(color)}
 	}
 
-	sortMoves(moves, b, depth)
-
-	var bestMoves []move.Move
-	var bestScore int
-	if maximizingPlayer {
-		bestScore = math.MinInt
-	} else {
-		bestScore = math.MaxInt
-	}
+	// Ход из таблицы, который намного лучше остальных, продлевается
+	singular := s.options.SingularExtension && ok && depth >= singularMinDepth && ply > 0 &&
+		entry.Depth >= depth-singularTTDepth && entry.Flag != boundUpper && containsMove(moves, entry.Move) &&
+		abs(entry.Score) < mateBound && s.stack[ply].extensions < s.options.MaxExtensions &&
+		s.isSingular(ctx, b, moves, entry.Move, scoreFromTT(entry.Score, ply), depth, ply, color, stats)
+
+	canPruneQuiets := !inCheck && !pvNode && depth <= 3
+	futile := s.options.Futility && canPruneQuiets && depth < len(futilityMargins) && staticEval+futilityMargins[depth] <= alpha
+
+	bestScore := -infinity
+	var bestMove move.Move
+	var pv []move.Move
+	searched := 0
+	var quiets []move.Move
+	list := s.newMoveList(b, moves, ply, ttMove)
+	for m, more := list.next(); more; m, more = list.next() {
+		targetPiece, _, _ := b.GetPiece(m.ToX, m.ToY)
+		piece, _, _ := b.GetPiece(m.FromX, m.FromY)
+		quiet := targetPiece == board.Empty && !(piece == board.Pawn && (m.ToX == 0 || m.ToX == 7))
 
-	for _, m := range moves {
 		newBoard := b.Copy()
 		if err := move.MakeMove(&newBoard, m); err != nil {
-			fmt.Printf("Ошибка в MakeMove для хода %v: %v\n", m, err)
+			log.Printf("Ошибка в MakeMove для хода %v: %v", m, err)
 			continue
 		}
-		res := Minimax(newBoard, depth-1, alpha, beta, !maximizingPlayer, deadline, stats)
-		stats.NodesEvaluated++
-		if maximizingPlayer {
-			if res.Score > bestScore {
-				bestScore = res.Score
-				bestMoves = []move.Move{m}
-			} else if res.Score == bestScore {
-				bestMoves = append(bestMoves, m)
+		givesCheck := (quiet || s.options.CheckExtension) && move.IsKingInCheck(newBoard, opponent(color))
+
+		if quiet && !givesCheck && searched > 0 {
+			if futile {
+				continue
 			}
-			alpha = max(alpha, bestScore)
-			if beta <= alpha {
-				updateKillerAndHistory(b, m, depth, color)
-				break
+			if s.options.LateMovePruning && canPruneQuiets && len(quiets) >= lateMoveCounts[depth] {
+				continue
 			}
+		}
+		stats.NodesEvaluated++
+		ext := s.extension(b, m, ply, givesCheck, singular && m == entry.Move)
+		s.enterPly(b, m, ply, ext)
+		s.traceMove(ply, m)
+		newDepth := depth - 1 + ext
+
+		var child SearchResult
+		if searched == 0 {
+			child = s.Negamax(ctx, newBoard, newDepth, ply+1, -beta, -alpha, opponent(color), true, stats)
 		} else {
-			if res.Score < bestScore {
-				bestScore = res.Score
-				bestMoves = []move.Move{m}
-			} else if res.Score == bestScore {
-				bestMoves = append(bestMoves, m)
+			// Поздние тихие ходы сначала проверяются на уменьшенной глубине
+			reduction := 0
+			if s.options.LMR && quiet && !givesCheck && !inCheck && ext == 0 && depth >= 3 && searched >= 3 && !s.isKiller(m, ply) {
+				reduction = 1
+				if searched >= 6 {
+					reduction = 2
+				}
+				if s.quietScore(piece, color, m, ply) > lmrHistoryThreshold {
+					reduction--
+				}
+				reduction = min(reduction, depth-2)
 			}
-			beta = min(beta, bestScore)
-			if beta <= alpha {
-				updateKillerAndHistory(b, m, depth, color)
-				break
+
+			child = s.Negamax(ctx, newBoard, newDepth-reduction, ply+1, -alpha-1, -alpha, opponent(color), true, stats)
+			if reduction > 0 && -child.Score > alpha {
+				child = s.Negamax(ctx, newBoard, newDepth, ply+1, -alpha-1, -alpha, opponent(color), true, stats)
+			}
+			if -child.Score > alpha && -child.Score < beta {
+				child = s.Negamax(ctx, newBoard, newDepth, ply+1, -beta, -alpha, opponent(color), true, stats)
+			}
+		}
+		score := -child.Score
+		searched++
+		if quiet {
+			quiets = append(quiets, m)
+		}
+
+		if score > bestScore {
+			bestScore = score
+			bestMove = m
+		}
+		if score > alpha {
+			alpha = score
+			pv = append([]move.Move{m}, child.PV...)
+		}
+		if alpha >= beta {
+			if quiet {
+				s.updateQuietStats(b, m, quiets, ply, depth)
 			}
+			s.traceCutoff(ply, cutoffBeta)
+			break
 		}
 	}
 
-	if len(bestMoves) == 0 {
-		fmt.Println("Не удалось найти лучшие ходы для", color)
-		return SearchResult{Score: evaluation.Evaluate(b)}
+	if searched == 0 {
+		log.Println("Не удалось найти лучшие ходы для", color)
+		return SearchResult{Score: evaluate(b, color)}
 	}
 
-	res := SearchResult{
-		BestMoves: bestMoves,
-		Score:     bestScore,
+	// Оценки прерванного поиска неточны и не должны попадать в таблицу
+	if ctx.Err() != nil {
+		return SearchResult{BestMoves: []move.Move{bestMove}, Score: bestScore, PV: pv}
 	}
-	transpositionTable.Lock()
-	transpositionTable.data[hash] = res
-	transpositionTable.Unlock()
-	return res
+
+	flag := boundExact
+	if bestScore <= alphaOrig {
+		flag = boundUpper
+	} else if bestScore >= beta {
+		flag = boundLower
+	}
+	s.storeTT(hash, ttEntry{Move: bestMove, Score: scoreToTT(bestScore, ply), Depth: depth, Flag: flag})
+
+	return SearchResult{BestMoves: []move.Move{bestMove}, Score: bestScore, PV: pv}
+}
+
+// scoreToTT переводит матовую оценку из отсчёта от корня в отсчёт от текущего узла,
+// чтобы запись таблицы оставалась верной при попадании в позицию на другой глубине
+func scoreToTT(score int, ply int) int {
+	if score > mateBound {
+		return score + ply
+	}
+	if score < -mateBound {
+		return score - ply
+	}
+	return score
+}
+
+// scoreFromTT выполняет обратное к scoreToTT преобразование
+func scoreFromTT(score int, ply int) int {
+	if score > mateBound {
+		return score - ply
+	}
+	if score < -mateBound {
+		return score + ply
+	}
+	return score
+}
+
+// MateIn возвращает число ходов до мата для матовой оценки score: положительное, если
+// мат ставит сторона, для которой дана оценка, отрицательное, если мат получает она.
+// Для обычной оценки возвращается 0.
+func MateIn(score int) int {
+	if score > mateBound {
+		return (MateScore - score + 1) / 2
+	}
+	if score < -mateBound {
+		return -(MateScore + score) / 2
+	}
+	return 0
+}
+
+// QuiescenceSearch продолжает поиск по взятиям, шахам и превращениям, чтобы оценка не
+// обрывалась посреди размена. Оценка возвращается с точки зрения стороны color.
+func (s *Searcher) QuiescenceSearch(ctx context.Context, b board.Board, ply int, alpha int, beta int, color board.Color, maxDepth int, stats *SearchStats) int {
+	if !s.tracing(ply) {
+		return s.quiescence(ctx, b, ply, alpha, beta, color, maxDepth, stats)
+	}
+	seq, saved := s.trace.enter(ply)
+	score := s.quiescence(ctx, b, ply, alpha, beta, color, maxDepth, stats)
+	s.trace.exit(TraceNode{Seq: seq, Ply: ply, Quiescence: true, Depth: maxDepth, Alpha: alpha, Beta: beta, Score: score}, saved)
+	return score
 }
 
-func QuiescenceSearch(b board.Board, alpha int, beta int, maximizingPlayer bool, maxDepth int, deadline time.Time, stats *SearchStats) int {
-	if time.Now().After(deadline) || maxDepth <= 0 {
+// quiescence — поиск взятий в узле без трассировки, см. QuiescenceSearch
+func (s *Searcher) quiescence(ctx context.Context, b board.Board, ply int, alpha int, beta int, color board.Color, maxDepth int, stats *SearchStats) int {
+	stats.SelDepth = max(stats.SelDepth, ply)
+	if ctx.Err() != nil || maxDepth <= 0 {
 		stats.NodesEvaluated++
-		return evaluation.Evaluate(b)
+		if ctx.Err() != nil {
+			s.traceCutoff(ply, cutoffStopped)
+		}
+		return evaluate(b, color)
 	}
 
-	standPat := evaluation.Evaluate(b)
+	standPat := evaluate(b, color)
 	stats.NodesEvaluated++
-	if maximizingPlayer {
-		if standPat >= beta {
-			return beta
+	if standPat >= beta {
+		s.traceCutoff(ply, cutoffStandPat)
+		return beta
+	}
+	alpha = max(alpha, standPat)
+
+	list := s.newMoveList(b, move.GenerateMoves(b, color), ply, move.Move{})
+	for m, more := list.next(); more; m, more = list.next() {
+		targetPiece, _, _ := b.GetPiece(m.ToX, m.ToY)
+		piece, _, _ := b.GetPiece(m.FromX, m.FromY)
+		newBoard := b.Copy()
+		if err := move.MakeMove(&newBoard, m); err != nil {
+			continue
+		}
+
+		// Слабые превращения в форсированных вариантах почти не нужны, их оставляем основному поиску
+		promotion := piece == board.Pawn && m.PromoteTo == board.Queen
+		givesCheck := move.IsKingInCheck(newBoard, board.Black) || move.IsKingInCheck(newBoard, board.White)
+		// Взятие, которое по SEE проигрывает материал, не поднимет оценку выше stand pat
+		if s.options.SEEPruning && targetPiece != board.Empty && !promotion && !givesCheck && evaluation.SEE(b, m) < 0 {
+			continue
 		}
-		alpha = max(alpha, standPat)
-	} else {
-		if standPat <= alpha {
-			return alpha
+
+		if targetPiece != board.Empty || givesCheck || promotion {
+			s.traceMove(ply, m)
+			score := -s.QuiescenceSearch(ctx, newBoard, ply+1, -beta, -alpha, opponent(color), maxDepth-1, stats)
+			alpha = max(alpha, score)
+			if alpha >= beta {
+				s.traceCutoff(ply, cutoffBeta)
+				break
+			}
 		}
-		beta = min(beta, standPat)
 	}
 
-	color := board.Black
-	if maximizingPlayer {
-		color = board.White
+	return alpha
+}
+
+// searchRoot перебирает ходы в корне в окне (alpha, beta) и собирает все ходы с лучшей оценкой.
+// Ходы после первого проверяются нулевым окном вокруг alpha-1, поэтому равные
+// по силе ходы не отсекаются и попадают в BestMoves. Ходы из excluded и не входящие
+// в searchMoves текущего поиска не рассматриваются.
+func (s *Searcher) searchRoot(ctx context.Context, b board.Board, depth int, alpha int, beta int, color board.Color, excluded []move.Move, stats *SearchStats) SearchResult {
+	moves := excludeMoves(restrictMoves(move.GenerateMoves(b, color), s.searchMoves), excluded)
+	if len(moves) == 0 {
+		return SearchResult{Score: evaluate(b, color)}
 	}
 
-	moves := move.GenerateMoves(b, color)
-	sortMoves(moves, b, 0)
+	var ttMove move.Move
+	hash := b.Hash(color)
+	s.tt.Lock()
+	if entry, ok := s.tt.data[hash]; ok {
+		ttMove = entry.Move
+	}
+	s.tt.Unlock()
 
-	for _, m := range moves {
-		targetPiece, _, _ := b.GetPiece(m.ToX, m.ToY)
-		piece, _, _ := b.GetPiece(m.FromX, m.FromY)
+	alphaOrig := alpha
+	bestScore := -infinity
+	var bestMoves []move.Move
+	var pvs [][]move.Move
+	list := s.newMoveList(b, moves, 0, ttMove)
+	for i := 0; ; i++ {
+		m, more := list.next()
+		if !more {
+			break
+		}
+		if ctx.Err() != nil && len(bestMoves) > 0 {
+			break
+		}
+		s.report(Info{Depth: depth, CurrMove: m, CurrMoveNumber: i + 1}, stats)
 		newBoard := b.Copy()
 		if err := move.MakeMove(&newBoard, m); err != nil {
+			log.Printf("Ошибка в MakeMove для хода %v: %v", m, err)
 			continue
 		}
+		stats.NodesEvaluated++
+		s.enterPly(b, m, 0, 0)
+		s.traceMove(0, m)
 
-		if targetPiece != board.Empty || move.IsKingInCheck(newBoard, board.Black) || move.IsKingInCheck(newBoard, board.White) ||
-			(piece == board.Pawn && (m.ToX == 0 || m.ToX == 7)) {
-			score := QuiescenceSearch(newBoard, alpha, beta, !maximizingPlayer, maxDepth-1, deadline, stats)
-			if maximizingPlayer {
-				alpha = max(alpha, score)
-				if alpha >= beta {
-					break
-				}
-			} else {
-				beta = min(beta, score)
-				if beta <= alpha {
-					break
-				}
+		var child SearchResult
+		if len(bestMoves) == 0 {
+			child = s.Negamax(ctx, newBoard, depth-1, 1, -beta, -alpha, opponent(color), true, stats)
+		} else {
+			child = s.Negamax(ctx, newBoard, depth-1, 1, -alpha, -alpha+1, opponent(color), true, stats)
+			if -child.Score >= alpha {
+				child = s.Negamax(ctx, newBoard, depth-1, 1, -beta, -alpha+1, opponent(color), true, stats)
 			}
 		}
+		score := -child.Score
+
+		// Вариант сохраняется для каждого равного хода: любой из них может быть выбран
+		pv := append([]move.Move{m}, child.PV...)
+		if score > bestScore {
+			bestScore = score
+			bestMoves = []move.Move{m}
+			pvs = [][]move.Move{pv}
+		} else if score == bestScore {
+			bestMoves = append(bestMoves, m)
+			pvs = append(pvs, pv)
+		}
+		alpha = max(alpha, bestScore)
+		if alpha >= beta {
+			break
+		}
+	}
+
+	// Поиск без части ходов не должен подменять лучший ход позиции в таблице
+	if len(bestMoves) > 0 && len(excluded) == 0 && len(s.searchMoves) == 0 && ctx.Err() == nil {
+		flag := boundExact
+		if bestScore <= alphaOrig {
+			flag = boundUpper
+		} else if bestScore >= beta {
+			flag = boundLower
+		}
+		s.storeTT(hash, ttEntry{Move: bestMoves[0], Score: bestScore, Depth: depth, Flag: flag})
 	}
 
-	if maximizingPlayer {
-		return alpha
+	res := SearchResult{BestMoves: bestMoves, Score: bestScore, bestPVs: pvs}
+	if len(pvs) > 0 {
+		res.PV = pvs[0]
 	}
-	return beta
+	return res
 }
 
-func FindBestMove(b board.Board, depth int, boardColor board.Color) (move.Move, SearchStats) {
-	rand.Seed(time.Now().UnixNano())
-	maximizingPlayer := (boardColor == board.White)
-	start := time.Now()
-	timeLimit := 10 * time.Second
-	deadline := start.Add(timeLimit)
+// searchAspiration ищет в узком окне вокруг оценки предыдущей итерации и
+// расширяет его при выходе оценки за границы
+func (s *Searcher) searchAspiration(ctx context.Context, b board.Board, depth int, prevScore int, color board.Color, stats *SearchStats) SearchResult {
+	if depth < aspirationMinDepth {
+		return s.searchRoot(ctx, b, depth, -infinity, infinity, color, nil, stats)
+	}
 
-	stats := SearchStats{}
-	res := Minimax(b, depth, math.MinInt, math.MaxInt, maximizingPlayer, deadline, &stats)
-	stats.SearchTime = time.Since(start)
+	delta := aspirationWindow
+	alpha, beta := prevScore-delta, prevScore+delta
+	for {
+		res := s.searchRoot(ctx, b, depth, alpha, beta, color, nil, stats)
+		if ctx.Err() != nil {
+			return res
+		}
+
+		switch {
+		case res.Score <= alpha:
+			stats.AspirationFailLows++
+			alpha -= delta
+		case res.Score >= beta:
+			stats.AspirationFailHighs++
+			beta += delta
+		default:
+			return res
+		}
 
-	if len(res.BestMoves) == 0 {
-		fmt.Println("Minimax вернул пустой список лучших ходов для", boardColor)
-		moves := move.GenerateMoves(b, boardColor)
-		if len(moves) == 0 {
-			fmt.Println("GenerateMoves вернул пустой список для", boardColor)
-			return move.Move{}, stats
+		delta *= 2
+		if delta > aspirationMaxWindow {
+			alpha, beta = -infinity, infinity
 		}
-		return moves[0], stats // Возвращаем первый доступный ход
+	}
+}
+
+// searchLines дополняет результат основного поиска следующими по силе ходами:
+// каждый очередной ход ищется в корне с исключением уже найденных
+func (s *Searcher) searchLines(ctx context.Context, b board.Board, depth int, main SearchResult, color board.Color, multiPV int, stats *SearchStats) []Line {
+	if len(main.PV) == 0 {
+		return nil
 	}
 
-	// Ограничиваем рандомизацию топ-3 ходами (или всеми, если их меньше)
-	maxChoices := 3
-	if len(res.BestMoves) < maxChoices {
-		maxChoices = len(res.BestMoves)
+	lines := []Line{{Score: main.Score, PV: main.PV}}
+	excluded := []move.Move{main.PV[0]}
+	for len(lines) < multiPV && ctx.Err() == nil {
+		res := s.searchRoot(ctx, b, depth, -infinity, infinity, color, excluded, stats)
+		if len(res.PV) == 0 {
+			break
+		}
+		lines = append(lines, Line{Score: res.Score, PV: res.PV})
+		excluded = append(excluded, res.PV[0])
 	}
-	// Сортируем ходы по эвристике для дебюта
-	sort.Slice(res.BestMoves, func(i, j int) bool {
-		return moveHeuristic(res.BestMoves[i]) > moveHeuristic(res.BestMoves[j])
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Score > lines[j].Score
 	})
-	// Выбираем случайный из топ-N
-	return res.BestMoves[rand.Intn(maxChoices)], stats
+	return lines
+}
+
+// excludeMoves возвращает ходы, которых нет в списке excluded
+func excludeMoves(moves []move.Move, excluded []move.Move) []move.Move {
+	if len(excluded) == 0 {
+		return moves
+	}
+	var result []move.Move
+	for _, m := range moves {
+		found := false
+		for _, e := range excluded {
+			if m == e {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// FormatPV возвращает вариант в виде строки ходов через пробел
+func FormatPV(pv []move.Move) string {
+	parts := make([]string, len(pv))
+	for i, m := range pv {
+		parts[i] = m.String()
+	}
+	return strings.Join(parts, " ")
 }
 
 // moveHeuristic добавляет приоритет центральным ходам в дебюте
@@ -287,99 +683,51 @@ func min(a, b int) int {
 	return b
 }
 
-func sortMoves(moves []move.Move, b board.Board, depth int) {
-	sort.Slice(moves, func(i, j int) bool {
-		moveI, moveJ := moves[i], moves[j]
-
-		if moveI.FromX < 0 || moveI.FromX >= 8 || moveI.FromY < 0 || moveI.FromY >= 8 ||
-			moveI.ToX < 0 || moveI.ToX >= 8 || moveI.ToY < 0 || moveI.ToY >= 8 ||
-			moveJ.FromX < 0 || moveJ.FromX >= 8 || moveJ.FromY < 0 || moveJ.FromY >= 8 ||
-			moveJ.ToX < 0 || moveJ.ToX >= 8 || moveJ.ToY < 0 || moveJ.ToY >= 8 {
-			return false
-		}
-
-		pieceI, colorI, _ := b.GetPiece(moveI.FromX, moveI.FromY)
-		pieceJ, colorJ, _ := b.GetPiece(moveJ.FromX, moveJ.FromY)
-		targetPieceI, _, _ := b.GetPiece(moveI.ToX, moveI.ToY)
-		targetPieceJ, _, _ := b.GetPiece(moveJ.ToX, moveJ.ToY)
-
-		scoreI := 0
-		if targetPieceI != board.Empty {
-			targetValue := evaluation.PieceValues[targetPieceI]
-			pieceValue := evaluation.PieceValues[pieceI]
-			scoreI += targetValue - pieceValue/10
-		}
-		if pieceI == board.Pawn && (moveI.ToX == 0 || moveI.ToX == 7) {
-			scoreI += 900
-		}
-		if pieceI == board.Knight || pieceI == board.Bishop {
-			scoreI += 20
-		}
-		if pieceI == board.Pawn && (moveI.ToY == 3 || moveI.ToY == 4) && targetPieceI == board.Empty {
-			scoreI += 20
-		}
-		if depth < len(killerMoves) {
-			if moveI == killerMoves[depth][0] {
-				scoreI += 1000
-			} else if moveI == killerMoves[depth][1] {
-				scoreI += 900
-			}
-		}
-		pieceIndexI := int(pieceI) + 6*int(colorI)
-		if pieceIndexI < 12 {
-			scoreI += history[pieceIndexI][moveI.ToX*8+moveI.ToY] / 100
-		}
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
 
-		scoreJ := 0
-		if targetPieceJ != board.Empty {
-			targetValue := evaluation.PieceValues[targetPieceJ]
-			pieceValue := evaluation.PieceValues[pieceJ]
-			scoreJ += targetValue - pieceValue/10
-		}
-		if pieceJ == board.Pawn && (moveJ.ToX == 0 || moveJ.ToX == 7) {
-			scoreJ += 900
-		}
-		if pieceJ == board.Knight || pieceJ == board.Bishop {
-			scoreJ += 20
-		}
-		if pieceJ == board.Pawn && (moveJ.ToY == 3 || moveJ.ToY == 4) && targetPieceJ == board.Empty {
-			scoreJ += 20
-		}
-		if depth < len(killerMoves) {
-			if moveJ == killerMoves[depth][0] {
-				scoreJ += 1000
-			} else if moveJ == killerMoves[depth][1] {
-				scoreJ += 900
-			}
-		}
-		pieceIndexJ := int(pieceJ) + 6*int(colorJ)
-		if pieceIndexJ < 12 {
-			scoreJ += history[pieceIndexJ][moveJ.ToX*8+moveJ.ToY] / 100
-		}
+// evaluate возвращает оценку позиции с точки зрения стороны color
+func evaluate(b board.Board, color board.Color) int {
+	if color == board.Black {
+		return -evaluation.Evaluate(b)
+	}
+	return evaluation.Evaluate(b)
+}
 
-		return scoreI > scoreJ
-	})
+func opponent(color board.Color) board.Color {
+	if color == board.White {
+		return board.Black
+	}
+	return board.White
 }
 
-func boardToString(b board.Board) string {
-	var s string
+// hasNonPawnMaterial проверяет, есть ли у стороны фигуры кроме короля и пешек
+func hasNonPawnMaterial(b board.Board, color board.Color) bool {
 	for i := 0; i < 8; i++ {
 		for j := 0; j < 8; j++ {
-			piece, color, _ := b.GetPiece(i, j)
-			s += fmt.Sprintf("%d%d", piece, color)
+			piece, pieceColor, _ := b.GetPiece(i, j)
+			if pieceColor == color && piece != board.Empty && piece != board.Pawn && piece != board.King {
+				return true
+			}
 		}
 	}
-	return s
+	return false
 }
 
-func updateKillerAndHistory(b board.Board, m move.Move, depth int, color board.Color) {
-	if depth < len(killerMoves) {
-		killerMoves[depth][1] = killerMoves[depth][0]
-		killerMoves[depth][0] = m
-	}
-	piece, _, _ := b.GetPiece(m.FromX, m.FromY)
-	pieceIndex := int(piece) + 6*int(color)
-	if pieceIndex < 12 {
-		history[pieceIndex][m.ToX*8+m.ToY] += depth * depth
+func (s *Searcher) isKiller(m move.Move, ply int) bool {
+	return m == s.killerMoves[ply][0] || m == s.killerMoves[ply][1]
+}
+
+// containsMove проверяет, есть ли ход m в списке moves
+func containsMove(moves []move.Move, m move.Move) bool {
+	for _, candidate := range moves {
+		if candidate == m {
+			return true
+		}
 	}
+	return false
 }
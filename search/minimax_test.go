@@ -0,0 +1,78 @@
+package search
+
+import (
+	"chess-engine/board"
+	"context"
+	"testing"
+)
+
+func TestMateIn(t *testing.T) {
+	tests := []struct {
+		score int
+		want  int
+	}{
+		{0, 0},
+		{350, 0},
+		{-mateBound, 0},
+		{MateScore - 1, 1},
+		{MateScore - 3, 2},
+		{MateScore - 4, 2},
+		{-MateScore + 2, -1},
+		{-MateScore + 4, -2},
+	}
+	for _, tt := range tests {
+		if got := MateIn(tt.score); got != tt.want {
+			t.Errorf("MateIn(%d) = %d, want %d", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestScoreTT(t *testing.T) {
+	tests := []struct {
+		score, ply int
+		stored     int // Оценка в таблице: для мата отсчёт от узла, а не от корня
+	}{
+		{120, 5, 120},
+		{-mateBound, 7, -mateBound},
+		{MateScore - 9, 4, MateScore - 5},
+		{-MateScore + 6, 2, -MateScore + 4},
+	}
+	for _, tt := range tests {
+		stored := scoreToTT(tt.score, tt.ply)
+		if stored != tt.stored {
+			t.Errorf("scoreToTT(%d, %d) = %d, want %d", tt.score, tt.ply, stored, tt.stored)
+		}
+		if got := scoreFromTT(stored, tt.ply); got != tt.score {
+			t.Errorf("scoreFromTT(%d, %d) = %d, want %d", stored, tt.ply, got, tt.score)
+		}
+	}
+	// Мат, сохранённый на полуходе 4, на полуходе 6 на два полухода дальше от корня
+	if got := scoreFromTT(scoreToTT(MateScore-9, 4), 6); got != MateScore-11 {
+		t.Errorf("мат с полухода 4 на полуходе 6 = %d, want %d", got, MateScore-11)
+	}
+}
+
+func TestFindBestMoveMateIn(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		want int
+	}{
+		{"мат в 1", "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", 1},
+		{"мат в 2", "7k/8/8/8/8/8/R7/1R4K1 w - - 0 1", 2},
+		{"мат в 1 соперника", "7k/4Q3/6K1/8/8/8/8/8 b - - 0 1", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, color, err := board.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := NewSearcher()
+			res, _ := s.FindBestMove(context.Background(), b, color, SearchLimits{Depth: 4, Infinite: true})
+			if res.MateIn != tt.want {
+				t.Errorf("MateIn = %d (оценка %d, вариант %s), want %d", res.MateIn, res.Score, FormatPV(res.PV), tt.want)
+			}
+		})
+	}
+}
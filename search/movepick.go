@@ -0,0 +1,172 @@
+package search
+
+import (
+	"chess-engine/board"
+	"chess-engine/evaluation"
+	"chess-engine/move"
+)
+
+// Оценки групп ходов в списке: внутри группы ходы упорядочены по MVV-LVA или истории
+const (
+	ttMoveScore       = 1 << 24
+	promotionScore    = 1 << 23
+	goodCaptureScore  = 1 << 22
+	killerScore       = 1 << 21 // Первый killer move; второй и ответный ход чуть ниже
+	badCaptureScore   = -(1 << 22)
+	historyMax        = 1 << 14 // Предел значений истории: обновления с «гравитацией» к нему не подходят
+	historyBonusLimit = 1536
+)
+
+// scoredMove — ход с оценкой для упорядочивания
+type scoredMove struct {
+	move  move.Move
+	score int
+}
+
+// moveList — список ходов узла, каждый из которых оценивается один раз. Ходы выдаются
+// по одному в порядке убывания оценки, поэтому после отсечения остальные не сортируются.
+type moveList struct {
+	moves []scoredMove
+}
+
+// newMoveList оценивает ходы узла на полуходе ply: сначала ход из таблицы, затем
+// превращения, взятия без потери материала по SEE в порядке MVV-LVA, killer moves,
+// ответный ход, тихие ходы по истории и продолжениям, последними — проигрывающие взятия
+func (s *Searcher) newMoveList(b board.Board, moves []move.Move, ply int, ttMove move.Move) *moveList {
+	l := &moveList{moves: make([]scoredMove, len(moves))}
+	for i, m := range moves {
+		l.moves[i] = scoredMove{move: m, score: s.scoreMove(b, m, ply, ttMove)}
+	}
+	return l
+}
+
+// next возвращает ход с наибольшей оценкой из ещё не выданных
+func (l *moveList) next() (move.Move, bool) {
+	if len(l.moves) == 0 {
+		return move.Move{}, false
+	}
+	best := 0
+	for i := 1; i < len(l.moves); i++ {
+		if l.moves[i].score > l.moves[best].score {
+			best = i
+		}
+	}
+	m := l.moves[best].move
+	l.moves[best] = l.moves[len(l.moves)-1]
+	l.moves = l.moves[:len(l.moves)-1]
+	return m, true
+}
+
+// scoreMove возвращает оценку хода m для упорядочивания
+func (s *Searcher) scoreMove(b board.Board, m move.Move, ply int, ttMove move.Move) int {
+	if m == ttMove {
+		return ttMoveScore
+	}
+	piece, color, _ := b.GetPiece(m.FromX, m.FromY)
+	target, _, _ := b.GetPiece(m.ToX, m.ToY)
+
+	score := 0
+	if piece == board.Pawn && (m.ToX == 0 || m.ToX == 7) && (m.PromoteTo == 0 || m.PromoteTo == board.Queen) {
+		score += promotionScore
+	}
+	if target != board.Empty {
+		// MVV-LVA: сначала самая ценная жертва, при равной — самый дешёвый нападающий
+		mvvLva := evaluation.PieceValues[target]*8 - evaluation.PieceValues[piece]/100
+		if evaluation.SEE(b, m) >= 0 {
+			return score + goodCaptureScore + mvvLva
+		}
+		return score + badCaptureScore + mvvLva
+	}
+	if score > 0 {
+		return score
+	}
+
+	switch {
+	case m == s.killerMoves[ply][0]:
+		return killerScore
+	case m == s.killerMoves[ply][1]:
+		return killerScore - 1
+	case m == s.counterMove(ply):
+		return killerScore - 2
+	}
+	return s.quietScore(piece, color, m, ply)
+}
+
+// quietScore возвращает оценку тихого хода по истории и истории продолжений
+func (s *Searcher) quietScore(piece board.Piece, color board.Color, m move.Move, ply int) int {
+	p, to := pieceIndex(piece, color), m.ToX*8+m.ToY
+	score := s.history[p][to]
+	for _, back := range [...]int{0, 1} {
+		if prev := s.prevMove(ply, back); prev.piece >= 0 {
+			score += s.continuation[prev.piece][prev.to][p][to]
+		}
+	}
+	return score
+}
+
+// prevMove возвращает ход, сделанный back+1 полуходов назад на пути к узлу ply,
+// или ход с piece < 0, если его нет (начало поиска или нулевой ход)
+func (s *Searcher) prevMove(ply int, back int) pathMove {
+	if ply-back < 1 {
+		return pathMove{piece: -1}
+	}
+	return s.stack[ply-back].lastMove
+}
+
+// counterMove возвращает ход, который последним опроверг предыдущий ход на пути к узлу ply
+func (s *Searcher) counterMove(ply int) move.Move {
+	prev := s.prevMove(ply, 0)
+	if prev.piece < 0 {
+		return move.Move{}
+	}
+	return s.counterMoves[prev.piece][prev.to]
+}
+
+// updateQuietStats поощряет тихий ход m, вызвавший отсечение на глубине depth,
+// и наказывает тихие ходы quiets, просмотренные до него без результата
+func (s *Searcher) updateQuietStats(b board.Board, m move.Move, quiets []move.Move, ply int, depth int) {
+	if s.killerMoves[ply][0] != m {
+		s.killerMoves[ply][1] = s.killerMoves[ply][0]
+		s.killerMoves[ply][0] = m
+	}
+	if prev := s.prevMove(ply, 0); prev.piece >= 0 {
+		s.counterMoves[prev.piece][prev.to] = m
+	}
+
+	bonus := min(32*depth*depth, historyBonusLimit)
+	s.updateHistory(b, m, ply, bonus)
+	for _, q := range quiets {
+		if q != m {
+			s.updateHistory(b, q, ply, -bonus)
+		}
+	}
+}
+
+// updateHistory изменяет историю хода и историю продолжений на bonus с «гравитацией»:
+// чем ближе значение к historyMax, тем меньше оно растёт, поэтому старые
+// успехи постепенно забываются
+func (s *Searcher) updateHistory(b board.Board, m move.Move, ply int, bonus int) {
+	piece, color, _ := b.GetPiece(m.FromX, m.FromY)
+	p, to := pieceIndex(piece, color), m.ToX*8+m.ToY
+	applyGravity(&s.history[p][to], bonus)
+	for _, back := range [...]int{0, 1} {
+		if prev := s.prevMove(ply, back); prev.piece >= 0 {
+			applyGravity(&s.continuation[prev.piece][prev.to][p][to], bonus)
+		}
+	}
+}
+
+func applyGravity(entry *int, bonus int) {
+	*entry += bonus - *entry*abs(bonus)/historyMax
+}
+
+// pieceIndex возвращает номер фигуры с учётом цвета от 0 до 11 для таблиц истории
+func pieceIndex(piece board.Piece, color board.Color) int {
+	return int(piece) - 1 + 6*int(color)
+}
+
+// pathMove — ход на пути от корня: фигура по pieceIndex и клетка, куда она пошла
+type pathMove struct {
+	piece int
+	to    int
+}
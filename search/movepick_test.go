@@ -0,0 +1,147 @@
+package search
+
+import (
+	"chess-engine/board"
+	"chess-engine/move"
+	"testing"
+)
+
+func mustMove(t *testing.T, s string) move.Move {
+	t.Helper()
+	m, err := move.ParseMove(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// orderedMoves возвращает ходы позиции fen в порядке, в котором их выдаёт moveList на полуходе ply
+func orderedMoves(t *testing.T, s *Searcher, fen string, ply int, ttMove move.Move) []string {
+	t.Helper()
+	b, color, err := board.ParseFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	list := s.newMoveList(b, move.GenerateMoves(b, color), ply, ttMove)
+	for m, more := list.next(); more; m, more = list.next() {
+		order = append(order, m.String())
+	}
+	return order
+}
+
+func TestMoveListOrder(t *testing.T) {
+	// Пешка e4 выгодно берёт коня d5, ферзь берёт его с потерей материала: конь защищён пешкой c6
+	const fen = "4k3/3p4/2p5/3n4/4P3/8/8/3QK3 w - - 0 1"
+	const ply = 1
+	s := NewSearcher()
+	s.killerMoves[ply] = [2]move.Move{mustMove(t, "d1a4"), mustMove(t, "d1d3")}
+	knight := pathMove{piece: pieceIndex(board.Knight, board.Black), to: 4*8 + 3}
+	s.stack[ply].lastMove = knight
+	s.counterMoves[knight.piece][knight.to] = mustMove(t, "e1f2")
+	s.history[pieceIndex(board.Queen, board.White)][3*8+6] = 500 // Ферзь на g4
+
+	got := orderedMoves(t, s, fen, ply, mustMove(t, "d1h5"))
+	want := []string{"d1h5", "e4d5", "d1a4", "d1d3", "e1f2", "d1g4"}
+	for i, m := range want {
+		if i >= len(got) || got[i] != m {
+			t.Fatalf("порядок ходов %v, want начало %v", got, want)
+		}
+	}
+	if last := got[len(got)-1]; last != "d1d5" {
+		t.Errorf("последний ход %s, want проигрывающее взятие d1d5 (порядок %v)", last, got)
+	}
+}
+
+func TestMoveListMVVLVA(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		want []string // Первые ходы в порядке выдачи
+	}{
+		// Незащищённые ферзь и конь: сначала самая ценная жертва
+		{"ценная жертва", "4k3/8/3q4/1n6/P1N5/8/8/4K3 w - - 0 1", []string{"c4d6", "a4b5"}},
+		// Одна жертва: сначала самый дешёвый нападающий
+		{"дешёвый нападающий", "4k3/8/8/3r4/2P5/8/3Q4/4K3 w - - 0 1", []string{"c4d5", "d2d5"}},
+		// Превращения в ферзя идут раньше взятий, слабые превращения — нет
+		{"превращение", "1n2k3/P7/8/8/8/8/8/4K3 w - - 0 1", []string{"a7b8q", "a7a8q"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := orderedMoves(t, NewSearcher(), tt.fen, 0, move.Move{})
+			for i, m := range tt.want {
+				if i >= len(got) || got[i] != m {
+					t.Fatalf("порядок ходов %v, want начало %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryGravity(t *testing.T) {
+	// Многократные успехи приближают значение к historyMax, но не переходят его
+	entry := 0
+	prev := 0
+	for i := 0; i < 1000; i++ {
+		applyGravity(&entry, historyBonusLimit)
+		if entry < prev || entry > historyMax {
+			t.Fatalf("шаг %d: история %d после %d, want рост не выше %d", i, entry, prev, historyMax)
+		}
+		prev = entry
+	}
+	if entry < historyMax*9/10 {
+		t.Errorf("история после 1000 успехов = %d, want близко к %d", entry, historyMax)
+	}
+
+	// Та же награда поднимает большое значение меньше, чем нулевое
+	high, zero := historyMax/2, 0
+	applyGravity(&high, 1000)
+	applyGravity(&zero, 1000)
+	if high-historyMax/2 >= zero {
+		t.Errorf("рост от %d = %d, от 0 = %d; want от большего значения меньше", historyMax/2, high-historyMax/2, zero)
+	}
+
+	// Неудачи опускают значение не ниже -historyMax
+	entry = 0
+	for i := 0; i < 1000; i++ {
+		applyGravity(&entry, -historyBonusLimit)
+	}
+	if entry < -historyMax || entry > -historyMax*9/10 {
+		t.Errorf("история после 1000 неудач = %d, want близко к %d", entry, -historyMax)
+	}
+}
+
+func TestUpdateQuietStats(t *testing.T) {
+	b, _, err := board.ParseFEN("4k3/8/8/8/8/8/8/3QK3 w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const ply = 2
+	s := NewSearcher()
+	prev := pathMove{piece: pieceIndex(board.King, board.Black), to: 7*8 + 4}
+	s.stack[ply].lastMove = prev
+	first, cut, tried := mustMove(t, "d1a4"), mustMove(t, "d1h5"), mustMove(t, "e1f1")
+	s.killerMoves[ply][0] = first
+
+	s.updateQuietStats(b, cut, []move.Move{tried, cut}, ply, 4)
+	if s.killerMoves[ply] != [2]move.Move{cut, first} {
+		t.Errorf("killer moves = %v, want [%v %v]", s.killerMoves[ply], cut, first)
+	}
+	if s.counterMoves[prev.piece][prev.to] != cut {
+		t.Errorf("ответный ход = %v, want %v", s.counterMoves[prev.piece][prev.to], cut)
+	}
+	queen, king := pieceIndex(board.Queen, board.White), pieceIndex(board.King, board.White)
+	if s.history[queen][4*8+7] <= 0 || s.history[king][0*8+5] >= 0 {
+		t.Errorf("история: ход с отсечением %d, просмотренный без результата %d; want > 0 и < 0",
+			s.history[queen][4*8+7], s.history[king][0*8+5])
+	}
+	if s.continuation[prev.piece][prev.to][queen][4*8+7] <= 0 {
+		t.Errorf("история продолжения = %d, want > 0", s.continuation[prev.piece][prev.to][queen][4*8+7])
+	}
+
+	// Повторное отсечение тем же ходом не вытесняет второй killer move
+	s.updateQuietStats(b, cut, nil, ply, 4)
+	if s.killerMoves[ply] != [2]move.Move{cut, first} {
+		t.Errorf("killer moves после повтора = %v, want [%v %v]", s.killerMoves[ply], cut, first)
+	}
+}
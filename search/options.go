@@ -0,0 +1,69 @@
+package search
+
+// MaxDrawValue — наибольшая по модулю Options.DrawValue: оценка ничьей должна оставаться
+// далеко от оценок мата, иначе ничья будет неотличима от мата
+const MaxDrawValue = 1000
+
+// Options включает и выключает отдельные эвристики выборочного поиска,
+// чтобы можно было измерить вклад каждой из них
+type Options struct {
+	NullMove        bool // Отсечение нулевым ходом
+	LMR             bool // Сокращение глубины для поздних ходов
+	ReverseFutility bool // Обратное отсечение бесперспективных узлов (static null move)
+	Futility        bool // Отсечение бесперспективных тихих ходов у листьев
+	LateMovePruning bool // Отсечение поздних тихих ходов у листьев
+	SEEPruning      bool // Отсечение проигрывающих по SEE взятий в поиске взятий
+
+	CheckExtension      bool // Продление шахов
+	SingularExtension   bool // Продление единственного хода из транспозиционной таблицы
+	RecaptureExtension  bool // Продление взятия в ответ на взятие на той же клетке
+	PassedPawnExtension bool // Продление хода пешкой на предпоследнюю горизонталь
+	MaxExtensions       int  // Наибольшее число продлений на одном пути от корня
+
+	// DrawValue — оценка ничьей повторением, по правилу пятидесяти ходов или патом для стороны,
+	// за которую ведётся поиск. Отрицательное значение заставляет избегать ничьих.
+	// Допустимы значения от -MaxDrawValue до MaxDrawValue.
+	DrawValue int
+
+	// TraceFile — файл, в который каждый поиск заново записывает просмотренное дерево
+	// (см. TraceNode); пустая строка выключает трассировку. TracePly — наибольшее
+	// расстояние от корня до записываемых узлов; 0 — defaultTracePly.
+	TraceFile string
+	TracePly  int
+
+	// Deterministic делает поиск воспроизводимым: каждый поиск начинается с пустых
+	// таблиц и не зависит ни от LoadData, ни от предыдущих поисков, а случайный выбор
+	// среди равных ходов и на ослабленном уровне берёт числа из генератора с начальным
+	// значением Seed. Одна и та же позиция с той же историей партии и ограничениями по
+	// глубине или узлам даёт один и тот же ход и число узлов; ограничение по времени
+	// по-прежнему зависит от скорости машины.
+	Deterministic bool
+	Seed          int64
+}
+
+// DefaultOptions возвращает настройки поиска по умолчанию: все отсечения включены,
+// из продлений — шахи и единственный ход
+func DefaultOptions() Options {
+	return Options{
+		NullMove:        true,
+		LMR:             true,
+		ReverseFutility: true,
+		Futility:        true,
+		LateMovePruning: true,
+		SEEPruning:      true,
+
+		CheckExtension:    true,
+		SingularExtension: true,
+		MaxExtensions:     defaultExtensions,
+	}
+}
+
+// SetOptions задаёт настройки для последующих поисков этого Searcher
+func (s *Searcher) SetOptions(o Options) {
+	s.options = o
+}
+
+// Options возвращает текущие настройки поиска
+func (s *Searcher) Options() Options {
+	return s.options
+}
@@ -0,0 +1,84 @@
+package search
+
+import (
+	"chess-engine/board"
+	"context"
+	"sync"
+	"time"
+)
+
+// Ponder — поиск на время соперника. Он ведётся в позиции после ожидаемого хода
+// соперника без ограничения по времени, пока соперник думает.
+type Ponder struct {
+	clock  *ponderClock
+	cancel context.CancelFunc
+	done   chan struct{}
+	result SearchResult
+	stats  SearchStats
+}
+
+// ponderClock — часы поиска на время соперника: время на ход начинает идти с Hit
+type ponderClock struct {
+	once sync.Once
+	hit  chan struct{} // Закрывается по Hit
+	at   time.Time     // Время Hit; читается только после закрытия hit
+}
+
+// elapsed возвращает, сколько времени прошло с Hit; до Hit — 0
+func (c *ponderClock) elapsed() time.Duration {
+	select {
+	case <-c.hit:
+		return time.Since(c.at)
+	default:
+		return 0
+	}
+}
+
+// withTimeout возвращает контекст, который отменяется через d после Hit
+func (c *ponderClock) withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-c.hit:
+			timer := time.AfterFunc(d-time.Since(c.at), cancel)
+			<-ctx.Done()
+			timer.Stop()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// StartPonder запускает в фоне поиск в позиции b, где ход за color, и сообщает о нём через OnInfo.
+// До Hit поиск не ограничен временем; ограничения времени из limits действуют с момента Hit.
+func (s *Searcher) StartPonder(b board.Board, color board.Color, limits SearchLimits) *Ponder {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Ponder{clock: &ponderClock{hit: make(chan struct{})}, cancel: cancel, done: make(chan struct{})}
+	limits.ponder = p.clock
+	go func() {
+		defer close(p.done)
+		p.result, p.stats = s.FindBestMove(ctx, b, color, limits)
+	}()
+	return p
+}
+
+// Hit сообщает, что соперник сделал ожидаемый ход: поиск продолжается с уже
+// накопленными таблицами, но с этого момента распределяет время на ход так же,
+// как обычный поиск с теми же limits
+func (p *Ponder) Hit() {
+	p.clock.once.Do(func() {
+		p.clock.at = time.Now()
+		close(p.clock.hit)
+	})
+}
+
+// Stop прерывает поиск, например когда соперник сыграл не тот ход
+func (p *Ponder) Stop() {
+	p.cancel()
+}
+
+// Result дожидается окончания поиска и возвращает его результат
+func (p *Ponder) Result() (SearchResult, SearchStats) {
+	<-p.done
+	return p.result, p.stats
+}
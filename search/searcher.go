@@ -0,0 +1,195 @@
+package search
+
+import (
+	"chess-engine/board"
+	"chess-engine/move"
+	"context"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Info — сведения о ходе поиска, которые Searcher передаёт в OnInfo.
+// После каждой завершённой итерации для каждого варианта MultiPV приходит Info
+// с заполненными Score и PV; перед проверкой каждого хода в корне — Info
+// с заполненными CurrMove и CurrMoveNumber и пустым PV.
+type Info struct {
+	Depth          int
+	SelDepth       int
+	MultiPV        int // Номер варианта, начиная с 1
+	Score          int // Оценка с точки зрения стороны, которая ходит
+	MateIn         int // Мат в N ходов, см. SearchResult.MateIn
+	Nodes          int
+	NPS            int
+	Hashfull       int // Заполненность транспозиционной таблицы в промилле
+	Time           time.Duration
+	PV             []move.Move
+	CurrMove       move.Move // Ход в корне, который проверяется сейчас
+	CurrMoveNumber int       // Номер этого хода в порядке перебора, начиная с 1
+}
+
+// Searcher — независимый экземпляр движка: у каждого свои транспозиционная таблица,
+// killer moves, история ходов и настройки, поэтому несколько Searcher могут искать
+// одновременно. Один Searcher ведёт не больше одного поиска за раз. О ходе поиска
+// Searcher сообщает через OnInfo, чтобы интерфейс мог показывать размышления без опроса.
+type Searcher struct {
+	OnInfo func(Info) // Вызывается из горутины поиска; nil — сведения не нужны
+
+	options      Options
+	tt           transpositionTable
+	killerMoves  [maxPly][2]move.Move // Killer moves по полуходу от корня
+	history      [12][64]int          // История тихих ходов по фигуре и клетке назначения
+	counterMoves [12][64]move.Move    // Ответный ход на предыдущий ход по его фигуре и клетке
+	continuation [12][64][12][64]int  // История тихих ходов в продолжение одного из двух предыдущих
+	stack        [maxPly]plyState
+	trace        *tracer    // Трассировка текущего поиска; nil — выключена
+	rng          *rand.Rand // Случайный выбор среди равных ходов и ходов ослабленной игры
+	gameHistory  []uint64   // Хеши позиций партии до корня, см. SetGameHistory
+	rule50       int        // Полуходов без взятий и ходов пешек к корню
+
+	start       time.Time   // Начало текущего поиска
+	rootColor   board.Color // Сторона, за которую ведётся текущий поиск
+	searchMoves []move.Move // Ходы, которыми ограничен перебор в корне текущего поиска
+}
+
+// NewSearcher создаёт Searcher с пустыми таблицами и настройками по умолчанию
+func NewSearcher() *Searcher {
+	s := &Searcher{options: DefaultOptions(), rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	s.tt.data = make(map[uint64]ttEntry)
+	return s
+}
+
+// FindBestMove ищет ход для стороны boardColor итеративным углублением в пределах limits.
+// Выбранный ход возвращается первым в BestMoves, PV содержит главный вариант, который с него начинается,
+// а Lines — до limits.MultiPV лучших ходов с их оценками и вариантами, по убыванию оценки.
+// Поиск прекращается при отмене ctx или по исчерпании limits; тогда возвращается
+// результат последней завершённой итерации. На ослабленном уровне limits.Skill ход
+// выбирается среди нескольких лучших вариантов. В режиме Options.Deterministic
+// поиск начинается с пустых таблиц и случайных чисел из Options.Seed.
+func (s *Searcher) FindBestMove(ctx context.Context, b board.Board, boardColor board.Color, limits SearchLimits) (SearchResult, SearchStats) {
+	if s.options.Deterministic {
+		s.Clear()
+		s.rng = rand.New(rand.NewSource(s.options.Seed))
+	}
+	limits = skillLimits(limits)
+	if s.options.Deterministic && (limits.Depth > 0 || limits.Nodes > 0) && limits.MoveTime == 0 && limits.WTime == 0 && limits.BTime == 0 {
+		// Время по умолчанию зависит от скорости машины и сделало бы результат невоспроизводимым
+		limits.Infinite = true
+	}
+	s.start = time.Now()
+	s.searchMoves = limits.SearchMoves
+	s.rootColor = boardColor
+	s.startTrace()
+	defer s.stopTrace()
+	s.stack[0] = plyState{captureSquare: noCaptureSquare, hash: b.Hash(boardColor), rule50: s.rule50, lastMove: pathMove{piece: -1}}
+	hard, soft := limits.timeBudget(boardColor)
+	elapsed := func() time.Duration { return time.Since(s.start) }
+	if limits.ponder != nil {
+		elapsed = limits.ponder.elapsed
+	}
+	if hard > 0 {
+		var cancel context.CancelFunc
+		if limits.ponder != nil {
+			ctx, cancel = limits.ponder.withTimeout(ctx, hard)
+		} else {
+			ctx, cancel = context.WithTimeout(ctx, hard)
+		}
+		defer cancel()
+	}
+
+	stats := SearchStats{}
+	if limits.Nodes > 0 {
+		ctx = nodeLimitContext{Context: ctx, stats: &stats, limit: limits.Nodes}
+	}
+
+	var res SearchResult
+	// Итеративное углубление: каждая итерация упорядочивает ходы для следующей
+	// через транспозиционную таблицу и задаёт центр окна поиска
+	for d := 1; d <= limits.maxDepth(); d++ {
+		s.traceIteration(d)
+		iteration := s.searchAspiration(ctx, b, d, res.Score, boardColor, &stats)
+		s.pickEqualMove(&iteration)
+		iteration.Lines = s.searchLines(ctx, b, d, iteration, boardColor, limits.multiPV(), &stats)
+		if ctx.Err() != nil {
+			stats.Stopped = true
+			if d > 1 {
+				break // Незавершённая итерация ненадёжна, используем предыдущую
+			}
+		}
+		res = iteration
+		res.MateIn = MateIn(res.Score)
+		stats.Depth = d
+		for i, line := range res.Lines {
+			s.report(Info{Depth: d, MultiPV: i + 1, Score: line.Score, MateIn: MateIn(line.Score), PV: line.PV}, &stats)
+		}
+		if stats.Stopped || limits.mateFound(res.Score) || (soft > 0 && elapsed() >= soft) {
+			break
+		}
+	}
+	stats.SearchTime = time.Since(s.start)
+
+	if len(res.BestMoves) == 0 {
+		moves := restrictMoves(move.GenerateMoves(b, boardColor), limits.SearchMoves)
+		if len(moves) == 0 {
+			return res, stats
+		}
+		// Возвращаем первый доступный ход
+		res.BestMoves = []move.Move{moves[0]}
+		res.PV = []move.Move{moves[0]}
+		return res, stats
+	}
+
+	if weakened(limits.Skill) && len(res.Lines) > 0 {
+		// Ослабленная игра: ход выбирается среди нескольких лучших вариантов
+		line := res.Lines[pickSkillMove(res.Lines, limits.Skill, s.rng)]
+		res.BestMoves = []move.Move{line.PV[0]}
+		res.PV = line.PV
+		res.Score = line.Score
+		res.MateIn = MateIn(line.Score)
+		return res, stats
+	}
+
+	return res, stats
+}
+
+// pickEqualMove выбирает случайный ход среди равных по оценке лучших ходов res и ставит
+// его первым в BestMoves вместе с его вариантом в PV. Выбор делается до сообщения
+// об итерации, поэтому выбранный ход совпадает с последним сообщённым вариантом.
+func (s *Searcher) pickEqualMove(res *SearchResult) {
+	if len(res.BestMoves) < 2 || len(res.bestPVs) != len(res.BestMoves) {
+		return
+	}
+	// Сортируем ходы по эвристике для дебюта; равные остаются в порядке перебора
+	order := make([]int, len(res.BestMoves))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return moveHeuristic(res.BestMoves[order[i]]) > moveHeuristic(res.BestMoves[order[j]])
+	})
+	moves := make([]move.Move, len(order))
+	pvs := make([][]move.Move, len(order))
+	for i, k := range order {
+		moves[i], pvs[i] = res.BestMoves[k], res.bestPVs[k]
+	}
+	// Ограничиваем рандомизацию топ-3 ходами (или всеми, если их меньше)
+	choice := s.rng.Intn(min(3, len(moves)))
+	moves[0], moves[choice] = moves[choice], moves[0]
+	pvs[0], pvs[choice] = pvs[choice], pvs[0]
+	res.BestMoves, res.bestPVs, res.PV = moves, pvs, pvs[0]
+}
+
+// report дополняет сведения счётчиками поиска и передаёт их в OnInfo
+func (s *Searcher) report(info Info, stats *SearchStats) {
+	if s.OnInfo == nil {
+		return
+	}
+	info.SelDepth = stats.SelDepth
+	info.Nodes = stats.NodesEvaluated
+	info.Time = time.Since(s.start)
+	if info.Time > 0 {
+		info.NPS = int(float64(info.Nodes) / info.Time.Seconds())
+	}
+	info.Hashfull = s.hashfull()
+	s.OnInfo(info)
+}
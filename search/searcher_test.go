@@ -0,0 +1,78 @@
+package search
+
+import (
+	"chess-engine/board"
+	"context"
+	"testing"
+)
+
+// noPruning возвращает настройки без выборочных отсечений и продлений: такой поиск
+// должен давать одну и ту же оценку при любом окне, в которое она попадает
+func noPruning() Options {
+	return Options{Deterministic: true}
+}
+
+func TestFindBestMove(t *testing.T) {
+	tests := []struct {
+		name  string
+		fen   string
+		depth int
+		want  string
+	}{
+		{"мат по последней горизонтали", "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", 2, "a1a8"},
+		{"взятие незащищённого ферзя", "4k3/8/8/3q4/8/8/3R4/4K3 w - - 0 1", 3, "d2d5"},
+		{"мат чёрными", "r5k1/8/8/8/8/8/5PPP/6K1 b - - 0 1", 1, "a8a1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, color, err := board.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := NewSearcher()
+			opts := s.Options()
+			opts.Deterministic = true
+			s.SetOptions(opts)
+			res, _ := s.FindBestMove(context.Background(), b, color, SearchLimits{Depth: tt.depth})
+			if len(res.BestMoves) == 0 || res.BestMoves[0].String() != tt.want {
+				t.Errorf("FindBestMove = %v, want %s", res.BestMoves, tt.want)
+			}
+			if len(res.PV) == 0 || res.PV[0] != res.BestMoves[0] {
+				t.Errorf("PV %v не начинается с хода %v", res.PV, res.BestMoves)
+			}
+		})
+	}
+}
+
+// Поиск с нулевым окном и перепроверкой (PVS) даёт ту же оценку, что и поиск
+// с полным окном, а оценка вне окна сообщается верной границей
+func TestNegamaxWindows(t *testing.T) {
+	fens := []string{
+		board.StartFEN,
+		"r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4",
+		"4k3/8/8/3q4/8/8/3R4/4K3 w - - 0 1",
+	}
+	for _, fen := range fens {
+		b, color, err := board.ParseFEN(fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		search := func(alpha, beta int) int {
+			s := NewSearcher()
+			s.SetOptions(noPruning())
+			s.rootColor = color
+			s.stack[0] = plyState{captureSquare: noCaptureSquare, hash: b.Hash(color), lastMove: pathMove{piece: -1}}
+			return s.Negamax(context.Background(), b, 3, 0, alpha, beta, color, false, &SearchStats{}).Score
+		}
+		score := search(-infinity, infinity)
+		if got := search(score-1, score+1); got != score {
+			t.Errorf("%s: оценка в окне (%d, %d) = %d, want %d", fen, score-1, score+1, got, score)
+		}
+		if got := search(score, score+1); got > score {
+			t.Errorf("%s: оценка в окне (%d, %d) = %d, want не больше %d", fen, score, score+1, got, score)
+		}
+		if got := search(score-1, score); got < score {
+			t.Errorf("%s: оценка в окне (%d, %d) = %d, want не меньше %d", fen, score-1, score, got, score)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package search
+
+import "math/rand"
+
+// MaxSkillLevel — уровень игры в полную силу; уровни от 1 до MaxSkillLevel-1 ослабляют движок
+const MaxSkillLevel = 20
+
+// Рейтинг уровней игры (индекс — уровень). Уровни сыграли по 32 партии (8 дебютов за оба
+// цвета; после 160 полуходов перевес больше 300 считается победой) против полной силы
+// с фиксированной глубиной 1 (уровни 1–8) и 2 (уровни 7, 8, 10, 12); глубина 2 сильнее
+// глубины 1 на ~420. Результат относительно глубины 1: 1: −273, 2: −206, 3: −191, 4: −89,
+// 5: −191, 6: +55, 7: −5, 8: +24, 10: +376, 12: +597. Погрешность около ±80, поэтому
+// значения сглажены до возрастающих, а уровни 9 и 11 интерполированы. С движками известной
+// силы уровни не сверялись: шкала сдвинута так, что уровень 1 — 800. Уровни выше 12 не
+// измерялись — партии на них слишком долгие — и рейтинга не имеют.
+var skillElo = [...]int{0, 800, 860, 880, 920, 940, 1080, 1100, 1120, 1270, 1450, 1560, 1670}
+
+// Границы рейтинга для SkillFromElo — рейтинги первого и последнего измеренного уровня
+const (
+	MinElo = 800
+	MaxElo = 1670
+)
+
+// Параметры ослабленной игры
+const (
+	skillMultiPV   = 4   // Из скольких лучших ходов выбирается ход ослабленной игры
+	skillBaseNodes = 100 // Бюджет узлов на уровне 1, удваивается каждые два уровня
+	skillMaxDelta  = 100 // Наибольший разброс оценок, в пределах которого выбор случаен
+)
+
+// SkillFromElo возвращает уровень игры с ближайшим к elo рейтингом
+func SkillFromElo(elo int) int {
+	elo = max(MinElo, min(MaxElo, elo))
+	level := 1
+	for l := 2; l < len(skillElo); l++ {
+		if abs(skillElo[l]-elo) < abs(skillElo[level]-elo) {
+			level = l
+		}
+	}
+	return level
+}
+
+// EloForSkill возвращает рейтинг уровня игры level; ok — false, если рейтинг уровня не измерялся
+func EloForSkill(level int) (elo int, ok bool) {
+	if level < 1 || level >= len(skillElo) {
+		return 0, false
+	}
+	return skillElo[level], true
+}
+
+// weakened сообщает, что уровень level ослабляет игру
+func weakened(level int) bool {
+	return level > 0 && level < MaxSkillLevel
+}
+
+// skillLimits ограничивает глубину и число узлов по уровню игры и запрашивает
+// несколько лучших вариантов, среди которых pickSkillMove выберет ход
+func skillLimits(limits SearchLimits) SearchLimits {
+	if !weakened(limits.Skill) {
+		return limits
+	}
+	if depth := 1 + limits.Skill/3; limits.Depth == 0 || limits.Depth > depth {
+		limits.Depth = depth
+	}
+	if nodes := skillBaseNodes << (limits.Skill / 2); limits.Nodes == 0 || limits.Nodes > nodes {
+		limits.Nodes = nodes
+	}
+	limits.MultiPV = max(limits.MultiPV, skillMultiPV)
+	return limits
+}
+
+// pickSkillMove выбирает из вариантов, упорядоченных по убыванию оценки, ход для уровня
+// level: каждый вариант получает случайную надбавку, тем большую, чем ниже уровень
+// и чем больше вариант уступает лучшему, поэтому слабый уровень чаще ошибается,
+// но не отдаёт мат. Возвращает номер выбранного варианта.
+func pickSkillMove(lines []Line, level int, rng *rand.Rand) int {
+	top := lines[0].Score
+	delta := min(top-lines[len(lines)-1].Score, skillMaxDelta)
+	weakness := 120 - 5*level
+
+	best, bestValue := 0, -infinity
+	for i, line := range lines {
+		if line.Score < -mateBound && top >= -mateBound {
+			continue // Ход, ведущий к мату, не выбирается, если есть другой
+		}
+		push := (weakness*(top-line.Score) + delta*rng.Intn(weakness)) / 128
+		if value := line.Score + push; value > bestValue {
+			best, bestValue = i, value
+		}
+	}
+	return best
+}
@@ -0,0 +1,104 @@
+package search
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSkillFromElo(t *testing.T) {
+	tests := []struct {
+		elo  int
+		want int
+	}{
+		{0, 1},
+		{MinElo, 1},
+		{MaxElo, len(skillElo) - 1},
+		{MaxElo + 500, len(skillElo) - 1},
+		{1000, 5}, // Ближе к 940, чем к 1080
+		{1020, 6},
+	}
+	for _, tt := range tests {
+		if got := SkillFromElo(tt.elo); got != tt.want {
+			t.Errorf("SkillFromElo(%d) = %d, want %d", tt.elo, got, tt.want)
+		}
+	}
+	// Уровень растёт с рейтингом, а рейтинг уровня переводится обратно в тот же уровень
+	prev := 0
+	for elo := MinElo; elo <= MaxElo; elo += 10 {
+		level := SkillFromElo(elo)
+		if level < prev {
+			t.Errorf("SkillFromElo(%d) = %d, меньше уровня %d для меньшего рейтинга", elo, level, prev)
+		}
+		prev = level
+	}
+	for level := 1; level < len(skillElo); level++ {
+		elo, ok := EloForSkill(level)
+		if !ok || level > 1 && elo <= skillElo[level-1] {
+			t.Errorf("EloForSkill(%d) = %d, %v; want рейтинг выше, чем у уровня %d", level, elo, ok, level-1)
+		}
+		if got := SkillFromElo(elo); got != level {
+			t.Errorf("SkillFromElo(EloForSkill(%d)) = %d", level, got)
+		}
+	}
+	if skillElo[1] != MinElo || skillElo[len(skillElo)-1] != MaxElo {
+		t.Errorf("MinElo, MaxElo = %d, %d; want рейтинги уровней 1 и %d", MinElo, MaxElo, len(skillElo)-1)
+	}
+	if _, ok := EloForSkill(MaxSkillLevel); ok {
+		t.Errorf("EloForSkill(%d): рейтинг полной силы не измерялся", MaxSkillLevel)
+	}
+}
+
+func TestSkillLimits(t *testing.T) {
+	full := SearchLimits{Depth: 10, Skill: MaxSkillLevel}
+	if got := skillLimits(full); got.Depth != 10 || got.Nodes != 0 || got.MultiPV != 0 {
+		t.Errorf("skillLimits на полной силе = %+v, want без изменений", got)
+	}
+	weak := skillLimits(SearchLimits{Skill: 1})
+	if weak.Depth == 0 || weak.Nodes == 0 || weak.MultiPV < skillMultiPV {
+		t.Errorf("skillLimits на уровне 1 = %+v, want ограничения глубины, узлов и MultiPV", weak)
+	}
+	strong := skillLimits(SearchLimits{Skill: MaxSkillLevel - 1})
+	if strong.Depth < weak.Depth || strong.Nodes <= weak.Nodes {
+		t.Errorf("skillLimits: уровень %d = %+v не сильнее уровня 1 = %+v", MaxSkillLevel-1, strong, weak)
+	}
+}
+
+func TestPickSkillMove(t *testing.T) {
+	// Вариант, ведущий к мату, не выбирается, пока есть другой
+	losing := []Line{{Score: 50}, {Score: 40}, {Score: -MateScore + 3}}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		if pick := pickSkillMove(losing, 1, rng); pick == 2 {
+			t.Fatalf("pickSkillMove выбрал ход, ведущий к мату")
+		}
+	}
+
+	// Слабый уровень ошибается чаще сильного, но сильный не всегда играет лучший ход
+	lines := []Line{{Score: 60}, {Score: 30}, {Score: 10}, {Score: -20}}
+	mistakes := func(level int) int {
+		rng := rand.New(rand.NewSource(2))
+		n := 0
+		for i := 0; i < 1000; i++ {
+			if pickSkillMove(lines, level, rng) != 0 {
+				n++
+			}
+		}
+		return n
+	}
+	weak, strong := mistakes(1), mistakes(MaxSkillLevel-1)
+	if weak <= strong {
+		t.Errorf("ошибок на уровне 1: %d, на уровне %d: %d; want на уровне 1 больше", weak, MaxSkillLevel-1, strong)
+	}
+	if weak == 0 || weak == 1000 {
+		t.Errorf("ошибок на уровне 1: %d из 1000, want выбор среди вариантов", weak)
+	}
+
+	// На сильном уровне вариант, намного уступающий лучшему, не выбирается
+	far := []Line{{Score: 300}, {Score: -300}}
+	rng = rand.New(rand.NewSource(3))
+	for i := 0; i < 1000; i++ {
+		if pickSkillMove(far, MaxSkillLevel-1, rng) != 0 {
+			t.Fatalf("pickSkillMove выбрал ход, уступающий лучшему 600")
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package search
+
+import (
+	"bufio"
+	"chess-engine/move"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+)
+
+// defaultTracePly — глубина трассировки от корня, если Options.TracePly не задана
+const defaultTracePly = 3
+
+// Причины, по которым узел завершился без полного перебора ходов
+const (
+	cutoffBeta            = "beta"     // Ход поднял оценку до beta
+	cutoffTT              = "tt"       // Оценка из транспозиционной таблицы
+	cutoffNullMove        = "null"     // Пропуск хода не опустил оценку ниже beta
+	cutoffReverseFutility = "rfp"      // Статическая оценка с запасом выше beta
+	cutoffStandPat        = "standpat" // В поиске взятий статическая оценка не ниже beta
+	cutoffDraw            = "draw"     // Повторение или правило пятидесяти ходов
+	cutoffMate            = "mate"     // Мат: ходов нет, король под шахом
+	cutoffStalemate       = "stalemate"
+	cutoffStopped         = "stopped" // Поиск прерван по времени, узлам или отмене
+)
+
+// TraceNode — запись об узле дерева поиска в файле трассировки. Файл содержит
+// по одному JSON-объекту на строку в порядке выхода из узлов; Seq — номер входа
+// в узел, поэтому ReadTrace восстанавливает порядок обхода дерева.
+type TraceNode struct {
+	Seq        int      `json:"seq"`
+	Iteration  int      `json:"iter"`         // Глубина итерации итеративного углубления
+	Ply        int      `json:"ply"`          // Полуходов от корня
+	Path       []string `json:"path"`         // Ходы от корня до узла; нулевой ход — "0000"
+	Quiescence bool     `json:"qs,omitempty"` // Узел поиска взятий
+	Depth      int      `json:"depth"`        // Оставшаяся глубина; в поиске взятий — оставшиеся полуходы взятий
+	Alpha      int      `json:"alpha"`
+	Beta       int      `json:"beta"`
+	Score      int      `json:"score"` // С точки зрения стороны, которая ходит в узле
+	TTHit      bool     `json:"tt,omitempty"`
+	Cutoff     string   `json:"cutoff,omitempty"`
+}
+
+// ReadTrace читает файл трассировки и возвращает узлы в порядке обхода дерева
+func ReadTrace(r io.Reader) ([]TraceNode, error) {
+	var nodes []TraceNode
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var node TraceNode
+		if err := json.Unmarshal(scanner.Bytes(), &node); err != nil {
+			return nil, fmt.Errorf("строка %d: %v", line, err)
+		}
+		nodes = append(nodes, node)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Seq < nodes[j].Seq })
+	return nodes, nil
+}
+
+// tracer записывает узлы одного поиска не глубже maxPly
+type tracer struct {
+	file      *os.File
+	w         *bufio.Writer
+	maxPly    int
+	iteration int
+	seq       int
+	path      [maxPly]move.Move // path[ply] — ход из узла ply в узел ply+1
+	nodes     [maxPly]traceState
+}
+
+// traceState — то, что узел узнаёт о себе во время поиска
+type traceState struct {
+	ttHit  bool
+	cutoff string
+}
+
+// startTrace открывает файл трассировки из Options.TraceFile для нового поиска
+func (s *Searcher) startTrace() {
+	if s.options.TraceFile == "" {
+		return
+	}
+	f, err := os.Create(s.options.TraceFile)
+	if err != nil {
+		log.Println("Ошибка создания файла трассировки:", err)
+		return
+	}
+	maxTracePly := s.options.TracePly
+	if maxTracePly <= 0 {
+		maxTracePly = defaultTracePly
+	}
+	s.trace = &tracer{file: f, w: bufio.NewWriter(f), maxPly: min(maxTracePly, maxPly-1)}
+}
+
+// stopTrace дописывает и закрывает файл трассировки
+func (s *Searcher) stopTrace() {
+	if s.trace == nil {
+		return
+	}
+	if err := s.trace.w.Flush(); err != nil {
+		log.Println("Ошибка записи трассировки:", err)
+	}
+	s.trace.file.Close()
+	s.trace = nil
+}
+
+// tracing сообщает, что узлы на полуходе ply записываются
+func (s *Searcher) tracing(ply int) bool {
+	return s.trace != nil && ply <= s.trace.maxPly
+}
+
+// traceIteration отмечает начало итерации итеративного углубления на глубину depth
+func (s *Searcher) traceIteration(depth int) {
+	if s.trace != nil {
+		s.trace.iteration = depth
+	}
+}
+
+// traceMove запоминает ход m из узла ply, чтобы записать путь к его потомкам
+func (s *Searcher) traceMove(ply int, m move.Move) {
+	if s.tracing(ply + 1) {
+		s.trace.path[ply] = m
+	}
+}
+
+// traceTTHit отмечает, что узел ply нашёл свою позицию в транспозиционной таблице
+func (s *Searcher) traceTTHit(ply int) {
+	if s.tracing(ply) {
+		s.trace.nodes[ply].ttHit = true
+	}
+}
+
+// traceCutoff записывает причину, по которой узел ply завершился досрочно
+func (s *Searcher) traceCutoff(ply int, reason string) {
+	if s.tracing(ply) {
+		s.trace.nodes[ply].cutoff = reason
+	}
+}
+
+// enter начинает запись узла ply и возвращает его номер и состояние узла
+// на том же полуходе, которое нужно восстановить после выхода (перепроверка
+// нулевого хода ищет на том же полуходе внутри узла)
+func (t *tracer) enter(ply int) (int, traceState) {
+	t.seq++
+	saved := t.nodes[ply]
+	t.nodes[ply] = traceState{}
+	return t.seq, saved
+}
+
+// exit записывает узел ply и восстанавливает состояние saved
+func (t *tracer) exit(node TraceNode, saved traceState) {
+	node.Iteration = t.iteration
+	node.Path = make([]string, node.Ply)
+	for i, m := range t.path[:node.Ply] {
+		node.Path[i] = m.String()
+		if m == (move.Move{}) {
+			node.Path[i] = "0000"
+		}
+	}
+	node.TTHit = t.nodes[node.Ply].ttHit
+	node.Cutoff = t.nodes[node.Ply].cutoff
+	t.nodes[node.Ply] = saved
+
+	data, err := json.Marshal(node)
+	if err != nil {
+		return
+	}
+	t.w.Write(data)
+	t.w.WriteByte('\n')
+}
@@ -0,0 +1,439 @@
+package uci
+
+import (
+	"bufio"
+	"chess-engine/board"
+	"chess-engine/evaluation"
+	"chess-engine/move"
+	"chess-engine/search"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultDepth — глубина поиска, если в команде go не указано ни одного ограничения
+const defaultDepth = 5
+
+// maxMultiPV — наибольшее число вариантов в режиме MultiPV
+const maxMultiPV = 10
+
+// currMoveDelay — через сколько после начала поиска печатаются строки currmove
+const currMoveDelay = time.Second
+
+type engine struct {
+	out      io.Writer
+	searcher *search.Searcher
+	mu       sync.Mutex // Защищает вывод, в который пишет и горутина поиска
+	board    board.Board
+	color    board.Color
+	history  []uint64 // Хеши позиций до текущей, для распознавания повторений
+	rule50   int      // Полуходов без взятий и ходов пешек
+	multiPV  int
+	// Сила игры: уровень из опции Skill Level или, при UCI_LimitStrength, из UCI_Elo
+	skillLevel    int
+	limitStrength bool
+	elo           int
+	cancel        context.CancelFunc // Прерывает текущий поиск
+	done          chan struct{}      // Закрывается, когда горутина поиска напечатала bestmove
+	ponder        *search.Ponder     // Поиск по команде go ponder до ponderhit
+	ponderHit     chan struct{}      // Закрывается по команде ponderhit
+}
+
+// Run обрабатывает команды протокола UCI из in и пишет ответы в out до команды quit
+func Run(in io.Reader, out io.Writer) {
+	e := &engine{out: out, board: board.NewBoard(), color: board.White, multiPV: 1,
+		skillLevel: search.MaxSkillLevel, elo: search.MaxElo}
+	e.searcher = search.NewSearcher()
+	e.searcher.OnInfo = e.info
+	// Веса оценки из файла, если он есть; иначе остаются веса по умолчанию
+	if _, err := os.Stat(evaluation.ParamsFile); err == nil {
+		if err := evaluation.UseParamsFile(evaluation.ParamsFile); err != nil {
+			e.printf("info string %v\n", err)
+		}
+	}
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "uci":
+			e.printf("id name ChessCorseWork\n")
+			e.printf("id author Будников А.С.\n")
+			e.printf("option name MultiPV type spin default 1 min 1 max %d\n", maxMultiPV)
+			e.printf("option name Ponder type check default false\n")
+			e.printf("option name DrawValue type spin default 0 min %d max %d\n", -search.MaxDrawValue, search.MaxDrawValue)
+			e.printf("option name Skill Level type spin default %d min 1 max %d\n", search.MaxSkillLevel, search.MaxSkillLevel)
+			e.printf("option name UCI_LimitStrength type check default false\n")
+			e.printf("option name UCI_Elo type spin default %d min %d max %d\n", search.MaxElo, search.MinElo, search.MaxElo)
+			e.printf("option name Deterministic type check default false\n")
+			e.printf("option name Seed type spin default 0 min 0 max %d\n", math.MaxInt32)
+			e.printf("option name EvalParams type string default %s\n", evaluation.ParamsFile)
+			e.printf("uciok\n")
+		case "isready":
+			e.printf("readyok\n")
+		case "ucinewgame":
+			e.stop()
+			e.searcher.Clear()
+			e.board, e.color = board.NewBoard(), board.White
+			e.history, e.rule50 = nil, 0
+		case "setoption":
+			if err := e.setOption(fields[1:]); err != nil {
+				e.printf("info string %v\n", err)
+			}
+		case "position":
+			e.stop()
+			if err := e.position(fields[1:]); err != nil {
+				e.printf("info string %v\n", err)
+			}
+		case "go":
+			e.stop()
+			e.start(fields[1:])
+		case "ponderhit":
+			e.hit()
+		case "stop":
+			e.stop()
+		case "quit":
+			e.stop()
+			return
+		}
+	}
+}
+
+// position разбирает команду position [startpos | fen <FEN>] [moves <ходы>]
+func (e *engine) position(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("не указана позиция")
+	}
+
+	movesAt := len(args)
+	for i, arg := range args {
+		if arg == "moves" {
+			movesAt = i
+			break
+		}
+	}
+
+	switch args[0] {
+	case "startpos":
+		e.board, e.color = board.NewBoard(), board.White
+		e.rule50 = 0
+	case "fen":
+		fen := strings.Join(args[1:movesAt], " ")
+		b, color, err := board.ParseFEN(fen)
+		if err != nil {
+			return err
+		}
+		e.board, e.color = b, color
+		e.rule50 = board.HalfmoveClock(fen)
+	default:
+		return fmt.Errorf("неизвестный тип позиции: %s", args[0])
+	}
+
+	e.history = nil
+	if movesAt < len(args) {
+		for _, s := range args[movesAt+1:] {
+			m, err := move.ParseMove(s)
+			if err != nil {
+				return err
+			}
+			piece, _, _ := e.board.GetPiece(m.FromX, m.FromY)
+			irreversible := piece == board.Pawn || !e.board.IsEmpty(m.ToX, m.ToY)
+			hash := e.board.Hash(e.color)
+			if err := move.MakeMove(&e.board, m); err != nil {
+				return fmt.Errorf("ход %s: %v", s, err)
+			}
+			e.history = append(e.history, hash)
+			e.rule50++
+			if irreversible {
+				e.rule50 = 0
+			}
+			e.color = opponent(e.color)
+		}
+	}
+	return nil
+}
+
+// setOption разбирает команду setoption name <имя> value <значение>
+func (e *engine) setOption(args []string) error {
+	// Имя опции может состоять из нескольких слов, например Skill Level
+	var name, value []string
+	var field *[]string
+	for _, arg := range args {
+		switch arg {
+		case "name":
+			field = &name
+		case "value":
+			field = &value
+		default:
+			if field != nil {
+				*field = append(*field, arg)
+			}
+		}
+	}
+	return e.applyOption(strings.Join(name, " "), strings.Join(value, " "))
+}
+
+// applyOption задаёт значение опции name
+func (e *engine) applyOption(name, value string) error {
+
+	switch strings.ToLower(name) {
+	case "multipv":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxMultiPV {
+			return fmt.Errorf("некорректное значение MultiPV: %s", value)
+		}
+		e.multiPV = n
+	case "ponder":
+		// Размышления включаются командой go ponder, отдельной настройки не требуется
+	case "drawvalue":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < -search.MaxDrawValue || n > search.MaxDrawValue {
+			return fmt.Errorf("некорректное значение DrawValue: %s", value)
+		}
+		opts := e.searcher.Options()
+		opts.DrawValue = n
+		e.searcher.SetOptions(opts)
+	case "skill level":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > search.MaxSkillLevel {
+			return fmt.Errorf("некорректное значение Skill Level: %s", value)
+		}
+		e.skillLevel = n
+	case "uci_limitstrength":
+		on, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("некорректное значение UCI_LimitStrength: %s", value)
+		}
+		e.limitStrength = on
+	case "uci_elo":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < search.MinElo || n > search.MaxElo {
+			return fmt.Errorf("некорректное значение UCI_Elo: %s", value)
+		}
+		e.elo = n
+	case "deterministic":
+		on, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("некорректное значение Deterministic: %s", value)
+		}
+		opts := e.searcher.Options()
+		opts.Deterministic = on
+		e.searcher.SetOptions(opts)
+	case "seed":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 || n > math.MaxInt32 {
+			return fmt.Errorf("некорректное значение Seed: %s", value)
+		}
+		opts := e.searcher.Options()
+		opts.Seed = n
+		e.searcher.SetOptions(opts)
+	case "evalparams":
+		// Повторная установка того же файла перечитывает его; <empty> — веса по умолчанию
+		p := evaluation.DefaultParams()
+		if value != "" && value != "<empty>" {
+			var err error
+			if p, err = evaluation.LoadParams(value); err != nil {
+				return fmt.Errorf("некорректное значение EvalParams: %v", err)
+			}
+		}
+		// Записи таблиц поиска оценены прежними весами
+		e.stop()
+		evaluation.SetParams(p)
+		e.searcher.Clear()
+	default:
+		return fmt.Errorf("неизвестная опция: %s", name)
+	}
+	return nil
+}
+
+func (e *engine) printf(format string, args ...any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	fmt.Fprintf(e.out, format, args...)
+}
+
+// start запускает поиск по команде go в отдельной горутине, чтобы во время
+// поиска можно было принять stop или ponderhit
+func (e *engine) start(args []string) {
+	limits, ponder, err := parseGo(args)
+	if err != nil {
+		e.printf("info string %v\n", err)
+	}
+	limits.MultiPV = e.multiPV
+	limits.Skill = e.skillLevel
+	if e.limitStrength {
+		limits.Skill = search.SkillFromElo(e.elo)
+	}
+	e.searcher.SetGameHistory(e.history, e.rule50)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.done = make(chan struct{})
+	if ponder {
+		e.ponder = e.searcher.StartPonder(e.board, e.color, limits)
+		e.ponderHit = make(chan struct{})
+	}
+
+	go func(p *search.Ponder, ponderHit chan struct{}) {
+		defer close(e.done)
+		if p == nil {
+			res, _ := e.searcher.FindBestMove(ctx, e.board, e.color, limits)
+			if limits.Infinite {
+				// В бесконечном анализе bestmove печатается только после stop
+				<-ctx.Done()
+			}
+			e.report(res)
+			return
+		}
+
+		stopPonder := context.AfterFunc(ctx, p.Stop)
+		defer stopPonder()
+		res, _ := p.Result()
+		// Во время размышлений bestmove печатается только после ponderhit или stop
+		select {
+		case <-ponderHit:
+		case <-ctx.Done():
+		}
+		e.report(res)
+	}(e.ponder, e.ponderHit)
+}
+
+// parseGo разбирает параметры команды go. Если не задано ни одно ограничение,
+// поиск идёт до глубины defaultDepth. Ошибка в одном параметре не мешает
+// разобрать остальные.
+func parseGo(args []string) (limits search.SearchLimits, ponder bool, err error) {
+	limited := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "ponder":
+			ponder = true
+			continue
+		case "infinite":
+			limits.Infinite = true
+			limited = true
+			continue
+		case "searchmoves":
+			for i+1 < len(args) {
+				m, moveErr := move.ParseMove(args[i+1])
+				if moveErr != nil {
+					break
+				}
+				limits.SearchMoves = append(limits.SearchMoves, m)
+				i++
+			}
+			continue
+		}
+
+		if i+1 >= len(args) {
+			break
+		}
+		n, convErr := strconv.Atoi(args[i+1])
+		if convErr != nil || n < 0 {
+			err = fmt.Errorf("некорректное значение %s: %s", args[i], args[i+1])
+			i++
+			continue
+		}
+		ms := time.Duration(n) * time.Millisecond
+		switch args[i] {
+		case "depth":
+			limits.Depth = n
+		case "nodes":
+			limits.Nodes = n
+		case "mate":
+			limits.Mate = n
+		case "movetime":
+			limits.MoveTime = ms
+		case "wtime":
+			limits.WTime = ms
+		case "btime":
+			limits.BTime = ms
+		case "winc":
+			limits.WInc = ms
+		case "binc":
+			limits.BInc = ms
+		case "movestogo":
+			limits.MovesToGo = n
+		default:
+			continue
+		}
+		limited = true
+		i++
+	}
+
+	if !limited {
+		limits.Depth = defaultDepth
+	}
+	return limits, ponder, err
+}
+
+// hit переводит размышления на время соперника в обычный поиск по времени из команды go ponder
+func (e *engine) hit() {
+	if e.ponder == nil {
+		return
+	}
+	e.ponder.Hit()
+	close(e.ponderHit)
+	e.ponder = nil
+}
+
+// stop прерывает текущий поиск и дожидается, пока он напечатает лучший найденный ход
+func (e *engine) stop() {
+	if e.cancel == nil {
+		return
+	}
+	e.cancel()
+	<-e.done
+	e.cancel = nil
+	e.ponder = nil
+}
+
+// info печатает сведения о ходе поиска: завершённые итерации и текущий ход в корне
+func (e *engine) info(info search.Info) {
+	if info.PV == nil {
+		if info.Time >= currMoveDelay {
+			e.printf("info depth %d currmove %v currmovenumber %d\n", info.Depth, info.CurrMove, info.CurrMoveNumber)
+		}
+		return
+	}
+	e.printf("info depth %d seldepth %d multipv %d score %s nodes %d nps %d hashfull %d time %d pv %s\n",
+		info.Depth, info.SelDepth, info.MultiPV, formatScore(info.Score), info.Nodes, info.NPS, info.Hashfull,
+		info.Time.Milliseconds(), search.FormatPV(info.PV))
+}
+
+// report печатает лучший ход и ожидаемый ответ соперника
+func (e *engine) report(res search.SearchResult) {
+	if len(res.BestMoves) == 0 {
+		e.printf("bestmove 0000\n")
+		return
+	}
+	if len(res.PV) > 1 {
+		e.printf("bestmove %v ponder %v\n", res.BestMoves[0], res.PV[1])
+		return
+	}
+	e.printf("bestmove %v\n", res.BestMoves[0])
+}
+
+// formatScore возвращает оценку в формате UCI: cp <сантипешки> или mate <ходы>
+func formatScore(score int) string {
+	if n := search.MateIn(score); n != 0 {
+		return fmt.Sprintf("mate %d", n)
+	}
+	return fmt.Sprintf("cp %d", score)
+}
+
+func opponent(color board.Color) board.Color {
+	if color == board.White {
+		return board.Black
+	}
+	return board.White
+}
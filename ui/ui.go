@@ -5,6 +5,7 @@ import (
 	"chess-engine/evaluation"
 	"chess-engine/move"
 	"chess-engine/search"
+	"context"
 	"fmt"
 	"image/color"
 	"log"
@@ -15,6 +16,7 @@ import (
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 	"github.com/faiface/beep"
 	"github.com/faiface/beep/mp3"
@@ -22,7 +24,8 @@ import (
 )
 
 const (
-	cellSize = 80
+	cellSize      = 80
+	analysisWidth = 280
 )
 
 var (
@@ -41,31 +44,62 @@ type ChessApp struct {
 	grid                 *fyne.Container
 	infoLabel            *widget.Label
 	logText              *widget.Entry
+	analysisText         *widget.Entry // Лучшие ходы текущей позиции в режиме MultiPV
+	analysisPanel        fyne.CanvasObject
 	positions            map[string]int // История позиций для правила трёхкратного повторения
+	hashes               []uint64       // Хеши позиций партии до текущей, для поиска ИИ
+	rule50               int            // Полуходов без взятий и ходов пешек
 	gameOver             bool           // Флаг окончания игры
 	aiThinking           bool           // Флаг, показывающий, что ИИ думает
 	moveCount            int            // Счётчик ходов для определения первого хода
 	paused               bool
 	aiDepth              int
+	lastPV               []move.Move        // Главный вариант последнего поиска ИИ
+	lastScore            int                // Оценка последнего поиска ИИ (положительно для белых)
+	multiPV              int                // Число вариантов в панели анализа
+	analyzing            bool               // Флаг, показывающий, что идёт анализ позиции
+	cancelSearch         context.CancelFunc // Прерывает текущий поиск ИИ или анализ
+	aiInterrupted        bool               // Поиск ИИ прерван паузой и будет запущен заново
+	pondering            bool               // ИИ думает, пока ходит игрок
+	ponderCheck          *widget.Check
+	ponder               *search.Ponder // Поиск ответа на ожидаемый ход игрока
+	ponderMove           move.Move      // Ожидаемый ход игрока
+	searcher             *search.Searcher
+	skill                int // Уровень игры ИИ, search.MaxSkillLevel — полная сила
+	skillSelect          *widget.Select
+	searchDone           chan struct{} // Закрывается, когда горутина поиска ИИ или анализа завершилась
+	paramsFile           string        // Файл весов оценки, который перечитывает ReloadEvalParams
+	customParams         bool          // Веса оценки не по умолчанию: таблицы поиска не сохраняются и не загружаются
 }
 
 func NewChessApp() *ChessApp {
-	// Загружаем данные ИИ при создании приложения
-	search.LoadData()
-
 	app := &ChessApp{
+		searcher:     search.NewSearcher(),
 		currentBoard: board.NewBoard(),
 		selectedX:    -1,
 		selectedY:    -1,
 		logText:      widget.NewEntry(),
+		analysisText: widget.NewEntry(),
 		positions:    make(map[string]int),
 		gameOver:     false,
 		aiThinking:   false,
 		moveCount:    0,
 		paused:       false,
 		aiDepth:      5,
+		multiPV:      3,
+		skill:        search.MaxSkillLevel,
+		paramsFile:   evaluation.ParamsFile,
 	}
 	app.positions[boardToString(app.currentBoard)] = 1
+	// Веса оценки из файла, если он есть; иначе остаются веса по умолчанию
+	if _, err := os.Stat(evaluation.ParamsFile); err == nil {
+		app.LoadEvalParams(evaluation.ParamsFile)
+	}
+	// Загружаем данные ИИ при создании приложения: сохранённые оценки посчитаны весами по умолчанию
+	if !app.customParams {
+		app.searcher.LoadData()
+	}
+	app.searcher.OnInfo = app.showThinking
 	return app
 }
 
@@ -81,6 +115,32 @@ func (appl *ChessApp) Run() {
 	appl.logText.Wrapping = fyne.TextWrapWord
 	appl.logText.Disable() // Используем Disable вместо SetReadOnly для Fyne 2.5.4
 
+	// Панель анализа справа от доски
+	appl.ponderCheck = widget.NewCheck("Думать во время хода игрока", appl.SetPondering)
+	appl.ponderCheck.SetChecked(appl.pondering)
+	appl.skillSelect = widget.NewSelect(skillNames(), func(name string) {
+		appl.SetSkillLevel(skillFromName(name))
+	})
+	appl.skillSelect.SetSelected(skillName(appl.skill))
+	appl.analysisText.MultiLine = true
+	appl.analysisText.Wrapping = fyne.TextWrapWord
+	appl.analysisText.Disable()
+	appl.analysisPanel = container.NewGridWrap(
+		fyne.NewSize(analysisWidth, cellSize*8),
+		container.NewBorder(
+			container.NewVBox(
+				widget.NewLabel("Анализ позиции"),
+				widget.NewButton("Анализировать", appl.Analyze),
+				widget.NewButton("Разбор оценки", appl.ShowEvalBreakdown),
+				appl.ponderCheck,
+				widget.NewLabel("Сила игры ИИ"),
+				appl.skillSelect,
+			),
+			nil, nil, nil,
+			appl.analysisText,
+		),
+	)
+
 	// Оборачиваем logText в контейнер с тёмным фоном
 	logContainer := container.NewMax(
 		canvas.NewRectangle(color.RGBA{R: 30, G: 30, B: 30, A: 255}),
@@ -91,15 +151,16 @@ func (appl *ChessApp) Run() {
 		nil,
 		container.NewVBox(appl.infoLabel, logContainer),
 		nil,
-		nil,
+		appl.analysisPanel,
 		appl.grid,
 	)
 	appl.window.SetContent(content)
-	appl.window.Resize(fyne.NewSize(cellSize*8, cellSize*8+100))
+	appl.window.Resize(fyne.NewSize(cellSize*8+analysisWidth, cellSize*8+100))
 
 	// Сохраняем данные ИИ при закрытии окна
 	appl.window.SetCloseIntercept(func() {
-		search.SaveData()
+		appl.stopSearch()
+		appl.saveData()
 		appl.window.Close()
 	})
 
@@ -123,6 +184,16 @@ func boardToString(b board.Board) string {
 	return s
 }
 
+// scoreText описывает оценку поиска с точки зрения белых, заменяя матовые оценки на «мат в N»
+func scoreText(score int) string {
+	if n := search.MateIn(score); n > 0 {
+		return fmt.Sprintf("белые ставят мат в %d", n)
+	} else if n < 0 {
+		return fmt.Sprintf("чёрные ставят мат в %d", -n)
+	}
+	return fmt.Sprintf("оценка %+d", score)
+}
+
 func (app *ChessApp) playMoveSound() {
 	go func() {
 		file, err := os.Open("moveSound.mp3")
@@ -149,6 +220,10 @@ func (app *ChessApp) handleCellClick(x, y int) {
 		app.infoLabel.SetText("Подождите, ИИ думает...")
 		return
 	}
+	if app.analyzing {
+		app.infoLabel.SetText("Подождите, идёт анализ позиции...")
+		return
+	}
 	if app.gameOver {
 		app.infoLabel.SetText("Игра завершена. Начните новую игру.")
 		return
@@ -178,9 +253,11 @@ func (app *ChessApp) handleCellClick(x, y int) {
 
 		availableMoves := app.getAvailableMoves(app.selectedX, app.selectedY)
 		isValidMove := false
-		for _, m := range availableMoves {
-			if m.ToX == x && m.ToY == y {
-				isValidMove = true
+		var m move.Move
+		for _, am := range availableMoves {
+			// Первым из ходов на клетку идёт превращение в ферзя
+			if am.ToX == x && am.ToY == y {
+				m, isValidMove = am, true
 				break
 			}
 		}
@@ -190,10 +267,11 @@ func (app *ChessApp) handleCellClick(x, y int) {
 			return
 		}
 
-		m := move.Move{FromX: app.selectedX, FromY: app.selectedY, ToX: x, ToY: y}
+		before := app.currentBoard
 		if err := move.MakeMove(&app.currentBoard, m); err != nil {
 			app.infoLabel.SetText("Некорректный ход: " + err.Error())
 		} else {
+			app.recordMove(before, m, board.White)
 			app.logMessage(fmt.Sprintf("Ход игрока (белые): %s%d-%s%d", string('a'+app.selectedY), app.selectedX+1, string('a'+y), x+1))
 			app.playMoveSound()
 			app.selectedX, app.selectedY = -1, -1
@@ -201,33 +279,39 @@ func (app *ChessApp) handleCellClick(x, y int) {
 			positionHash := boardToString(app.currentBoard)
 			app.positions[positionHash]++
 			app.updateBoard()
+			ponder := app.resolvePonder(m)
 
 			if move.IsKingInCheck(app.currentBoard, board.Black) && app.isCheckmate(board.Black) {
 				app.infoLabel.SetText("Мат! Белые победили.")
 				app.logMessage("Игра завершена: мат чёрным. Победитель: Белые")
 				app.gameOver = true
-				search.SaveData()
+				app.stopPonder(ponder)
+				app.saveData()
 				return
 			} else if app.isCheckmate(board.Black) {
 				app.infoLabel.SetText("Пат! Ничья.")
 				app.logMessage("Игра завершена: пат для чёрных")
 				app.gameOver = true
-				search.SaveData()
+				app.stopPonder(ponder)
+				app.saveData()
 				return
 			} else if app.positions[positionHash] >= 3 {
 				app.infoLabel.SetText("Ничья по правилу трёхкратного повторения!")
 				app.logMessage("Игра завершена: ничья по правилу трёхкратного повторения")
 				app.gameOver = true
-				search.SaveData()
+				app.stopPonder(ponder)
+				app.saveData()
 				return
 			}
 
-			app.makeAIMove()
+			app.makeAIMove(ponder)
 		}
 	}
 }
 
-func (app *ChessApp) makeAIMove() {
+// makeAIMove ищет и делает ход ИИ. Если p не nil, вместо нового поиска используется
+// поиск, начатый во время хода игрока, который угадал ход игрока.
+func (app *ChessApp) makeAIMove(p *search.Ponder) {
 	if app.gameOver {
 		app.infoLabel.SetText("Игра завершена. Начните новую игру.")
 		return
@@ -235,8 +319,31 @@ func (app *ChessApp) makeAIMove() {
 
 	app.aiThinking = true
 	app.infoLabel.SetText("ИИ думает...")
+	ctx, cancel := context.WithCancel(context.Background())
+	app.cancelSearch = cancel
+	done := make(chan struct{})
+	app.searchDone = done
 	go func() {
-		bestMove, _ := search.FindBestMove(app.currentBoard, app.aiDepth, board.Black)
+		defer close(done)
+		defer cancel()
+		var res search.SearchResult
+		if p != nil {
+			p.Hit()
+			stopPonder := context.AfterFunc(ctx, p.Stop)
+			res, _ = p.Result()
+			stopPonder()
+		} else {
+			app.searcher.SetGameHistory(app.hashes, app.rule50)
+			res, _ = app.searcher.FindBestMove(ctx, app.currentBoard, board.Black, search.SearchLimits{Depth: app.aiDepth, Skill: app.skill})
+		}
+		if ctx.Err() != nil {
+			// Поиск отменён сбросом, паузой или выходом — состояние уже обновил тот, кто его отменил
+			return
+		}
+		var bestMove move.Move
+		if len(res.BestMoves) > 0 {
+			bestMove = res.BestMoves[0]
+		}
 		var message string
 		if bestMove.FromX == 0 && bestMove.FromY == 0 && bestMove.ToX == 0 && bestMove.ToY == 0 {
 			app.logMessage("ИИ не нашёл допустимых ходов")
@@ -246,11 +353,16 @@ func (app *ChessApp) makeAIMove() {
 				message = "Пат! Ничья."
 			}
 		} else {
+			before := app.currentBoard
 			if err := move.MakeMove(&app.currentBoard, bestMove); err != nil {
 				app.logMessage(fmt.Sprintf("Ошибка при выполнении хода ИИ: %v", err))
 				message = "Ошибка ИИ: " + err.Error()
 			} else {
+				app.recordMove(before, bestMove, board.Black)
 				app.logMessage(fmt.Sprintf("Ход ИИ (чёрные): %s%d-%s%d", string('a'+bestMove.FromY), bestMove.FromX+1, string('a'+bestMove.ToY), bestMove.ToX+1))
+				app.lastPV = res.PV
+				app.lastScore = -res.Score
+				app.logMessage(fmt.Sprintf("Главный вариант: %s (%s)", search.FormatPV(app.lastPV), scoreText(app.lastScore)))
 				app.playMoveSound()
 				app.moveCount++
 				positionHash := boardToString(app.currentBoard)
@@ -272,11 +384,120 @@ func (app *ChessApp) makeAIMove() {
 		app.aiThinking = false
 		app.gameOver = message != "ИИ сделал ход. Ваш ход."
 		if app.gameOver {
-			search.SaveData()
+			app.saveData()
+		} else {
+			app.startPonder(res.PV)
 		}
 	}()
 }
 
+// showThinking по ходу поиска ИИ или анализа показывает текущую глубину и ход в строке
+// состояния, а завершённые итерации выводит в консоль. Размышления во время хода игрока
+// не показываются, пока игрок не сделает ожидаемый ход.
+func (app *ChessApp) showThinking(info search.Info) {
+	title := "ИИ думает"
+	if app.analyzing {
+		title = "Анализ"
+	} else if !app.aiThinking {
+		return
+	} else {
+		info.Score = -info.Score // ИИ играет чёрными, а scoreText ожидает оценку с точки зрения белых
+	}
+
+	if info.PV == nil {
+		app.infoLabel.SetText(fmt.Sprintf("%s... глубина %d, ход %d: %v", title, info.Depth, info.CurrMoveNumber, info.CurrMove))
+		return
+	}
+	if info.MultiPV == 1 {
+		app.infoLabel.SetText(fmt.Sprintf("%s... глубина %d, %s, %s", title, info.Depth, scoreText(info.Score), search.FormatPV(info.PV)))
+	}
+	log.Printf("%s: глубина %d/%d, вариант %d, оценка %s, узлов %d (%d/с), %s",
+		title, info.Depth, info.SelDepth, info.MultiPV, scoreText(info.Score), info.Nodes, info.NPS, search.FormatPV(info.PV))
+}
+
+// recordMove добавляет в историю партии позицию before, из которой сторона color сделала ход m
+func (app *ChessApp) recordMove(before board.Board, m move.Move, color board.Color) {
+	app.hashes = append(app.hashes, before.Hash(color))
+	app.rule50 = rule50After(before, m, app.rule50)
+}
+
+// rule50After возвращает счётчик правила пятидесяти ходов после хода m в позиции b
+func rule50After(b board.Board, m move.Move, rule50 int) int {
+	piece, _, _ := b.GetPiece(m.FromX, m.FromY)
+	if piece == board.Pawn || !b.IsEmpty(m.ToX, m.ToY) {
+		return 0
+	}
+	return rule50 + 1
+}
+
+// startPonder запускает поиск ответа на ход игрока, который ИИ ожидает по главному варианту
+func (app *ChessApp) startPonder(pv []move.Move) {
+	if !app.pondering || len(pv) < 2 {
+		return
+	}
+	b := app.currentBoard
+	if err := move.MakeMove(&b, pv[1]); err != nil {
+		return
+	}
+	app.ponderMove = pv[1]
+	history := append(append([]uint64(nil), app.hashes...), app.currentBoard.Hash(board.White))
+	app.searcher.SetGameHistory(history, rule50After(app.currentBoard, pv[1], app.rule50))
+	app.ponder = app.searcher.StartPonder(b, board.Black, search.SearchLimits{Depth: app.aiDepth, Skill: app.skill})
+	log.Printf("ИИ ожидает ход %v и думает над ответом", pv[1])
+}
+
+// resolvePonder сравнивает ход игрока с ожидаемым. При совпадении возвращает поиск,
+// начатый во время хода игрока, иначе прерывает его и возвращает nil.
+func (app *ChessApp) resolvePonder(m move.Move) *search.Ponder {
+	p := app.ponder
+	if p == nil {
+		return nil
+	}
+	app.ponder = nil
+	if m == app.ponderMove {
+		log.Println("Игрок сделал ожидаемый ход, ИИ продолжает начатый поиск")
+		return p
+	}
+	app.stopPonder(p)
+	return nil
+}
+
+// stopPonder прерывает поиск на время игрока и дожидается его окончания,
+// чтобы он не шёл одновременно со следующим поиском
+func (app *ChessApp) stopPonder(p *search.Ponder) {
+	if p == nil {
+		return
+	}
+	p.Stop()
+	p.Result()
+}
+
+// SetPondering включает и выключает размышления ИИ во время хода игрока
+func (app *ChessApp) SetPondering(on bool) {
+	if app.pondering == on {
+		return
+	}
+	app.pondering = on
+	if !on {
+		app.stopPonder(app.ponder)
+		app.ponder = nil
+	}
+	if on {
+		log.Println("Размышления во время хода игрока включены")
+	} else {
+		log.Println("Размышления во время хода игрока выключены")
+	}
+}
+
+// TogglePondering переключает размышления ИИ во время хода игрока
+func (app *ChessApp) TogglePondering() {
+	if app.ponderCheck != nil {
+		app.ponderCheck.SetChecked(!app.pondering)
+		return
+	}
+	app.SetPondering(!app.pondering)
+}
+
 func (app *ChessApp) createCell(x, y int) fyne.CanvasObject {
 	lightColor := color.RGBA{R: 240, G: 217, B: 181, A: 255}
 	darkColor := color.RGBA{R: 181, G: 136, B: 99, A: 255}
@@ -409,7 +630,7 @@ func (app *ChessApp) isCheckmate(color board.Color) bool {
 
 func (app *ChessApp) updateBoard() {
 	app.grid = app.createBoardGrid()
-	app.window.SetContent(container.NewBorder(nil, container.NewVBox(app.infoLabel, container.NewMax(canvas.NewRectangle(color.RGBA{R: 30, G: 30, B: 30, A: 255}), app.logText)), nil, nil, app.grid))
+	app.window.SetContent(container.NewBorder(nil, container.NewVBox(app.infoLabel, container.NewMax(canvas.NewRectangle(color.RGBA{R: 30, G: 30, B: 30, A: 255}), app.logText)), nil, app.analysisPanel, app.grid))
 	app.window.Content().Refresh()
 }
 
@@ -430,14 +651,44 @@ func (app *ChessApp) PrintLastMoveEval() {
 		log.Println("Нет ходов для оценки")
 		return
 	}
-	score := evaluation.Evaluate(app.currentBoard)
-	log.Printf("Оценка позиции: %d (положительно для белых)", score)
+	log.Printf("Оценка позиции (положительно для белых):\n%s", evaluation.Trace(app.currentBoard))
+	if len(app.lastPV) > 0 {
+		log.Printf("Главный вариант ИИ: %s (%s)", search.FormatPV(app.lastPV), scoreText(app.lastScore))
+	}
+}
+
+// ShowEvalBreakdown показывает в отдельном окне, из каких признаков складывается
+// статическая оценка текущей позиции
+func (app *ChessApp) ShowEvalBreakdown() {
+	table := widget.NewLabel(evaluation.Trace(app.currentBoard).String())
+	table.TextStyle = fyne.TextStyle{Monospace: true}
+	dialog.ShowCustom("Разбор оценки (положительно для белых)", "Закрыть", table, app.window)
+}
+
+// stopSearch прерывает текущий поиск ИИ или анализ позиции, если он идёт, и дожидается
+// его окончания: следующий поиск использует те же таблицы Searcher
+func (app *ChessApp) stopSearch() {
+	if app.cancelSearch != nil {
+		app.cancelSearch()
+		app.cancelSearch = nil
+		<-app.searchDone
+	}
+	app.stopPonder(app.ponder)
+	app.ponder = nil
+	app.aiThinking = false
+	app.analyzing = false
 }
 
 func (app *ChessApp) Pause() {
-	if app.aiThinking {
-		log.Println("Невозможно поставить на паузу. ИИ делает ход")
-		return
+	if !app.paused {
+		// На паузе не идут ни поиск хода ИИ, ни анализ, ни обдумывание на время соперника;
+		// ход ИИ будет найден заново после возобновления игры
+		thinking := app.aiThinking
+		app.stopSearch()
+		if thinking {
+			app.aiInterrupted = true
+			log.Println("Поиск хода ИИ прерван")
+		}
 	}
 	app.paused = !app.paused
 	if app.paused {
@@ -445,35 +696,210 @@ func (app *ChessApp) Pause() {
 		app.infoLabel.SetText("Игра приостановлена")
 	} else {
 		log.Println("Игра возобновлена")
+		if app.aiInterrupted {
+			app.aiInterrupted = false
+			app.makeAIMove(nil)
+			return
+		}
 		app.infoLabel.SetText("Ваш ход. Выберите фигуру.")
 	}
 }
 
+// Analyze ищет лучшие ходы белых в текущей позиции и показывает их в панели анализа и в консоли
+func (app *ChessApp) Analyze() {
+	if app.aiThinking || app.analyzing {
+		log.Println("Невозможно начать анализ. ИИ делает ход")
+		return
+	}
+	if app.gameOver {
+		log.Println("Игра завершена, анализировать нечего")
+		return
+	}
+
+	app.stopPonder(app.ponder)
+	app.ponder = nil
+	app.analyzing = true
+	app.analysisText.SetText("Анализ...")
+	app.searcher.SetGameHistory(app.hashes, app.rule50)
+	ctx, cancel := context.WithCancel(context.Background())
+	app.cancelSearch = cancel
+	done := make(chan struct{})
+	app.searchDone = done
+	go func() {
+		defer close(done)
+		defer cancel()
+		res, stats := app.searcher.FindBestMove(ctx, app.currentBoard, board.White, search.SearchLimits{Depth: app.aiDepth, MultiPV: app.multiPV})
+		if ctx.Err() != nil {
+			app.analysisText.SetText("Анализ прерван")
+			return
+		}
+		text := fmt.Sprintf("Глубина %d, узлов %d\n", stats.Depth, stats.NodesEvaluated)
+		for i, line := range res.Lines {
+			text += fmt.Sprintf("%d. %s  %s\n", i+1, scoreText(line.Score), search.FormatPV(line.PV))
+		}
+		log.Print("Анализ позиции:\n" + text)
+		app.analysisText.SetText(text)
+		app.analyzing = false
+	}()
+}
+
+// SetMultiPV задаёт число вариантов, которые показывает анализ позиции
+func (app *ChessApp) SetMultiPV(n int) {
+	app.multiPV = n
+	log.Printf("Число вариантов анализа установлено на %d", n)
+}
+
+// skillName возвращает название уровня игры для списка в панели анализа
+func skillName(level int) string {
+	if level >= search.MaxSkillLevel {
+		return "Полная сила"
+	}
+	if elo, ok := search.EloForSkill(level); ok {
+		return fmt.Sprintf("Уровень %d (~%d Elo)", level, elo)
+	}
+	return fmt.Sprintf("Уровень %d", level)
+}
+
+// skillNames возвращает названия всех уровней игры от слабого к полной силе
+func skillNames() []string {
+	names := make([]string, 0, search.MaxSkillLevel)
+	for level := 1; level <= search.MaxSkillLevel; level++ {
+		names = append(names, skillName(level))
+	}
+	return names
+}
+
+// skillFromName возвращает уровень игры по его названию из skillNames
+func skillFromName(name string) int {
+	for level := 1; level <= search.MaxSkillLevel; level++ {
+		if skillName(level) == name {
+			return level
+		}
+	}
+	return search.MaxSkillLevel
+}
+
+// SetSkillLevel задаёт уровень игры ИИ от 1 до search.MaxSkillLevel (полная сила).
+// На слабых уровнях ИИ ищет на меньшую глубину и иногда выбирает не лучший ход.
+func (app *ChessApp) SetSkillLevel(level int) {
+	if app.skill == level {
+		return
+	}
+	app.skill = level
+	if app.skillSelect != nil {
+		app.skillSelect.SetSelected(skillName(level))
+	}
+	log.Printf("Сила игры ИИ: %s", skillName(level))
+}
+
+// SetDrawValue задаёт оценку ничьей для ИИ: отрицательная заставляет его избегать
+// повторений и ничьих по правилу пятидесяти ходов, положительная — стремиться к ним
+func (app *ChessApp) SetDrawValue(n int) {
+	opts := app.searcher.Options()
+	opts.DrawValue = n
+	app.searcher.SetOptions(opts)
+	log.Printf("Оценка ничьей установлена на %d", n)
+}
+
+// SetTrace включает запись дерева каждого поиска ИИ в файл path не глубже ply
+// полуходов от корня (0 — по умолчанию); пустой path выключает трассировку.
+// Файл перезаписывается каждым поиском и просматривается командой cmd/traceview.
+func (app *ChessApp) SetTrace(path string, ply int) {
+	opts := app.searcher.Options()
+	opts.TraceFile, opts.TracePly = path, ply
+	app.searcher.SetOptions(opts)
+	if path == "" {
+		log.Println("Трассировка поиска выключена")
+		return
+	}
+	log.Printf("Трассировка поиска записывается в %s", path)
+}
+
+// SetSeed включает воспроизводимую игру ИИ с начальным значением случайных чисел seed:
+// каждый поиск начинается с пустых таблиц, и одна и та же позиция даёт один и тот же ход.
+// on == false возвращает обычную игру.
+func (app *ChessApp) SetSeed(seed int64, on bool) {
+	opts := app.searcher.Options()
+	opts.Deterministic, opts.Seed = on, seed
+	app.searcher.SetOptions(opts)
+	if !on {
+		log.Println("Воспроизводимый режим выключен")
+		return
+	}
+	log.Printf("Воспроизводимый режим с начальным значением %d", seed)
+}
+
+// LoadEvalParams загружает веса оценки из JSON-файла path (см. evaluation.LoadParams);
+// пустой path возвращает веса по умолчанию. При ошибке ИИ играет с прежними весами.
+// Таблицы поиска хранят оценки прежних весов, поэтому очищаются, а ход, который ИИ ищет
+// сейчас, ищется заново.
+func (app *ChessApp) LoadEvalParams(path string) {
+	p := evaluation.DefaultParams()
+	if path != "" {
+		var err error
+		if p, err = evaluation.LoadParams(path); err != nil {
+			log.Printf("Ошибка загрузки весов оценки: %v", err)
+			return
+		}
+	}
+	thinking := app.aiThinking
+	app.stopSearch()
+	evaluation.SetParams(p)
+	app.searcher.Clear()
+	app.customParams = path != ""
+	if path == "" {
+		log.Println("Веса оценки сброшены на значения по умолчанию")
+	} else {
+		app.paramsFile = path
+		log.Printf("Веса оценки загружены из %s", path)
+	}
+	if thinking {
+		app.makeAIMove(nil)
+	}
+}
+
+// ReloadEvalParams перечитывает файл весов оценки, загруженный последним
+func (app *ChessApp) ReloadEvalParams() {
+	app.LoadEvalParams(app.paramsFile)
+}
+
+// saveData сохраняет таблицы поиска, если они посчитаны весами оценки по умолчанию
+func (app *ChessApp) saveData() {
+	if !app.customParams {
+		app.searcher.SaveData()
+	}
+}
+
 func (app *ChessApp) SetAIDepth(depth int) {
 	app.aiDepth = depth
 	log.Printf("Глубина поиска ИИ установлена на %d", depth)
 }
 
 func (app *ChessApp) Reset() {
+	app.stopSearch()
+	app.aiInterrupted = false
 	app.currentBoard = board.NewBoard()
 	app.selectedX, app.selectedY = -1, -1
 	app.positions = make(map[string]int)
 	app.positions[boardToString(app.currentBoard)] = 1
+	app.hashes, app.rule50 = nil, 0
 	app.gameOver = false
-	app.aiThinking = false
 	app.moveCount = 0
 	app.paused = false
+	app.lastPV = nil
+	app.lastScore = 0
 	app.updateBoard()
 	app.infoLabel.SetText("Игра сброшена. Ваш ход.")
 	log.Println("Игра сброшена")
 }
 
 func (app *ChessApp) Exit(flag int) {
+	app.stopSearch()
 	if flag == 0 {
 		app.window.Close()
 		return
 	} else {
-		search.SaveData()
+		app.saveData()
 		app.window.Close()
 	}
 }